@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+	"github.com/spf13/cobra"
+)
+
+var assertExamples = []string{
+	`kubectl get pods -A | tabloid assert --expr 'isready(ready) && hasnorestarts(restarts)' --where 'namespace == "prod"'`,
+	`kubectl get pods -A | tabloid assert --expr 'status == "Running"' --junit report.xml`,
+}
+
+type assertSettings struct {
+	expr      string
+	where     string
+	columns   []string
+	junit     string
+	suiteName string
+}
+
+func assertCommand(r io.Reader, opts *settings) *cobra.Command {
+	var aopts assertSettings
+
+	cmd := &cobra.Command{
+		Use:   "assert",
+		Short: "Verify that all rows satisfy an expression, failing otherwise",
+		Long: `Verify that all rows satisfy an expression, failing otherwise.
+
+Rows can be narrowed down first with --where. If any of the remaining rows
+does not satisfy --expr, the violating rows are printed along with a summary
+and tabloid exits with code 1.`,
+		Example: sliceToTabulated(assertExamples),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applySnapshot(cmd.Flags().Changed, opts); err != nil {
+				return err
+			}
+
+			if err := resolveOutput(opts); err != nil {
+				return err
+			}
+
+			return runAssert(r, os.Stdout, os.Stderr, *opts, aopts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&aopts.expr, "expr", "e", "", "expression every row must satisfy")
+	cmd.Flags().StringVarP(&aopts.where, "where", "w", "", "expression to select the rows to verify")
+	cmd.Flags().StringSliceVarP(&aopts.columns, "column", "c", []string{}, "columns to display for the violating rows")
+	cmd.Flags().StringVar(&aopts.junit, "junit", "", "write a JUnit XML report to the given file")
+	cmd.Flags().StringVar(&aopts.suiteName, "junit-suite", "tabloid", "name of the test suite in the JUnit XML report")
+
+	return cmd
+}
+
+func runAssert(r io.Reader, w, errw io.Writer, opts settings, aopts assertSettings) error {
+	if strings.TrimSpace(aopts.expr) == "" {
+		return fmt.Errorf("an expression to assert is required, use --expr to set it")
+	}
+
+	tab, cols, err := load(r, opts)
+	if err != nil {
+		return err
+	}
+
+	selected, err := tab.Filter(cols, aopts.where)
+	if err != nil {
+		return err
+	}
+
+	_, violations, err := tab.Partition(selected, aopts.expr)
+	if err != nil {
+		return err
+	}
+
+	display, err := tab.Select(violations, aopts.columns)
+	if err != nil {
+		return err
+	}
+
+	if aopts.junit != "" {
+		// The report lists the rows in their original order, so every
+		// row's result is needed.
+		results, err := tab.Matches(selected, aopts.expr)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tab.Select(selected, aopts.columns)
+		if err != nil {
+			return err
+		}
+
+		if err := writeJUnit(aopts.junit, aopts.suiteName, aopts.expr, rows, results); err != nil {
+			return err
+		}
+	}
+
+	total := rowsIn(selected)
+	failed := rowsIn(violations)
+
+	if failed == 0 {
+		fmt.Fprintf(errw, "assertion passed: all %d rows satisfy %q\n", total, aopts.expr)
+		return nil
+	}
+
+	if err := printOutput(w, display, opts); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(errw, "assertion failed: %d of %d rows do not satisfy %q\n", failed, total, aopts.expr)
+	return &exitError{code: exitCodeNoMatch}
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Details string `xml:",chardata"`
+}
+
+// writeJUnit writes a JUnit XML report where every verified row is a test
+// case, named after its values, in the same order as the rows.
+func writeJUnit(filename, suiteName, expr string, rows []tabloid.Column, results []bool) error {
+	suite := junitTestSuite{
+		Name:  suiteName,
+		Tests: len(results),
+	}
+
+	for i, passed := range results {
+		testCase := junitTestCase{
+			Name:      junitCaseName(rows, i),
+			ClassName: suiteName,
+		}
+
+		if !passed {
+			details := make([]string, 0, len(rows))
+			for _, c := range rows {
+				details = append(details, fmt.Sprintf("%s=%q", c.ExprTitle, c.Values[i]))
+			}
+
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("row does not satisfy %q", expr),
+				Details: strings.Join(details, " "),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	out, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to generate JUnit report: %w", err)
+	}
+
+	if err := os.WriteFile(filename, append([]byte(xml.Header), append(out, '\n')...), 0o644); err != nil {
+		return fmt.Errorf("unable to write JUnit report: %w", err)
+	}
+
+	return nil
+}
+
+// junitCaseName joins the values of a row to use them as a test case name.
+func junitCaseName(columns []tabloid.Column, row int) string {
+	values := make([]string, 0, len(columns))
+	for _, c := range columns {
+		values = append(values, c.Values[row])
+	}
+
+	return strings.Join(values, " / ")
+}
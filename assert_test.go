@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runAssert(t *testing.T) {
+	input := podsInput + "kube-system dns     1/1     Running   0          9d\n"
+
+	tests := []struct {
+		name       string
+		aopts      assertSettings
+		wantCode   int
+		wantOutput string
+		wantErr    bool
+	}{
+		{
+			name:  "all rows pass",
+			aopts: assertSettings{expr: `restarts != "5"`},
+		},
+		{
+			name:       "violating rows are printed",
+			aopts:      assertSettings{expr: `status == "Running"`, columns: []string{"name", "status"}},
+			wantCode:   exitCodeNoMatch,
+			wantOutput: "NAME,STATUS\nweb-2,Error\n",
+		},
+		{
+			name:  "where narrows the rows to verify",
+			aopts: assertSettings{expr: `status == "Running"`, where: `namespace == "kube-system"`},
+		},
+		{
+			name:       "where keeps violating rows",
+			aopts:      assertSettings{expr: `restarts == "0"`, where: `namespace == "default"`, columns: []string{"name"}},
+			wantCode:   exitCodeNoMatch,
+			wantOutput: "NAME\nweb-2\n",
+		},
+		{
+			name:    "missing expression",
+			aopts:   assertSettings{where: `namespace == "default"`},
+			wantErr: true,
+		},
+		{
+			name:    "invalid expression",
+			aopts:   assertSettings{expr: `status ==`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSettings()
+			opts.output = outputCSV
+
+			var out, errOut bytes.Buffer
+			err := runAssert(strings.NewReader(input), &out, &errOut, opts, tt.aopts)
+
+			code := 0
+			if exitErr, ok := err.(*exitError); ok {
+				code = exitErr.code
+			} else if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+
+			if got := out.String(); got != tt.wantOutput {
+				t.Errorf("output = %q, want %q", got, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func Test_runAssert_junit(t *testing.T) {
+	input := `NAME    STATUS
+web-1   Running
+web-2   Error
+web-3   Running
+a<&>b   "Off"
+`
+
+	report := filepath.Join(t.TempDir(), "report.xml")
+	aopts := assertSettings{expr: `status == "Running"`, junit: report, suiteName: "pods"}
+
+	var out, errOut bytes.Buffer
+	if err := runAssert(strings.NewReader(input), &out, &errOut, testSettings(), aopts); err == nil {
+		t.Fatal("expected the assertion to fail")
+	}
+
+	b, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatalf("unable to read report: %s", err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="pods" tests="4" failures="2">
+    <testcase name="web-1 / Running" classname="pods"></testcase>
+    <testcase name="web-2 / Error" classname="pods">
+      <failure message="row does not satisfy &#34;status == \&#34;Running\&#34;&#34;">name=&#34;web-2&#34; status=&#34;Error&#34;</failure>
+    </testcase>
+    <testcase name="web-3 / Running" classname="pods"></testcase>
+    <testcase name="a&lt;&amp;&gt;b / &#34;Off&#34;" classname="pods">
+      <failure message="row does not satisfy &#34;status == \&#34;Running\&#34;&#34;">name=&#34;a&lt;&amp;&gt;b&#34; status=&#34;\&#34;Off\&#34;&#34;</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if got := string(b); got != want {
+		t.Errorf("report =\n%s\nwant\n%s", got, want)
+	}
+}
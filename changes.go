@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// streamChanges reads the input line by line, like the output of
+// "kubectl get --watch", and prints every row that is new or changed as
+// soon as it's read. Since streams never include a full snapshot, removed
+// rows can't be detected. With --limit, it stops reading once that amount of
+// rows is printed.
+func streamChanges(r io.Reader, w io.Writer, opts settings) error {
+	feed := tabloid.NewChangeFeed()
+
+	tab, err := newTabloid(nil, opts)
+	if err != nil {
+		return err
+	}
+
+	var (
+		heading []tabloid.Column
+		printer = &eventPrinter{w: w, opts: opts}
+		left    = opts.limit
+	)
+
+	// Rows are processed one at a time, so the limit is applied to the
+	// printed rows instead.
+	rowOpts := opts
+	rowOpts.limit = 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if tab.SkipLine(line) {
+			continue
+		}
+
+		if heading == nil {
+			heading, err = tab.ParseHeading(line)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		cols, err := derive(tab, tab.ParseRow(heading, line), rowOpts)
+		if err != nil {
+			return err
+		}
+
+		output, keys, err := changedRows(tab, cols, rowOpts)
+		if err != nil {
+			return err
+		}
+
+		events, err := feed.Update(output, keys, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if opts.limit > 0 {
+			events = tab.Page(events, 0, left)
+			left -= rowsIn(events)
+		}
+
+		if err := printer.print(events); err != nil {
+			return err
+		}
+
+		if opts.limit > 0 && left == 0 {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error while scanning input: %w", err)
+	}
+
+	if heading == nil {
+		return fmt.Errorf("no data found in input")
+	}
+
+	return nil
+}
+
+// watchChanges runs the command on an interval and prints the rows that are
+// new, changed or removed since the previous run, until the context is
+// cancelled.
+func watchChanges(ctx context.Context, w, errw io.Writer, args []string, opts settings) error {
+	feed := tabloid.NewChangeFeed()
+
+	ticker := time.NewTicker(opts.watch)
+	defer ticker.Stop()
+
+	printer := &eventPrinter{w: w, opts: opts}
+
+	for {
+		err := watchChangesOnce(ctx, feed, printer, args, opts)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			fmt.Fprintf(errw, "Error: %s\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func watchChangesOnce(ctx context.Context, feed *tabloid.ChangeFeed, printer *eventPrinter, args []string, opts settings) error {
+	stdout, _, err := runSource(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	tab, cols, err := load(stdout, opts)
+	if err != nil {
+		return err
+	}
+
+	output, keys, err := changedRows(tab, cols, opts)
+	if err != nil {
+		return err
+	}
+
+	events, err := feed.Snapshot(output, keys, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return printer.print(events)
+}
+
+// changedRows processes the rows to compare, like process does, along with
+// their --key columns, which are taken from all the columns so they don't
+// need to be displayed.
+func changedRows(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) ([]tabloid.Column, []tabloid.Column, error) {
+	filtered, output, err := project(tab, cols, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys, err := tab.Select(filtered, opts.keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for --key: %w", err)
+	}
+
+	// Keys are limited along with the displayed columns, so their rows
+	// still match.
+	limited := limitRows(tab, append(output, keys...), opts)
+	return limited[:len(output)], limited[len(output):], nil
+}
+
+// eventPrinter prints batches of change events, only printing the table
+// titles for the first batch.
+type eventPrinter struct {
+	w       io.Writer
+	opts    settings
+	printed bool
+}
+
+func (p *eventPrinter) print(events []tabloid.Column) error {
+	if rowsIn(events) == 0 {
+		return nil
+	}
+
+	opts := p.opts
+	opts.noTitles = opts.noTitles || p.printed
+	p.printed = true
+
+	return printOutput(p.w, events, opts)
+}
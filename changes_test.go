@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_streamChanges_keysNotDisplayed(t *testing.T) {
+	input := podsInput + "default     web-2   1/1     Running   3          1d\n"
+
+	opts := testSettings()
+	opts.changes = true
+	opts.keys = []string{"name"}
+	opts.columns = []string{"status"}
+	opts.noTitles = true
+
+	var out bytes.Buffer
+	if err := streamChanges(strings.NewReader(input), &out, opts); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var events []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fields := strings.Fields(line)
+		events = append(events, fields[0]+" "+fields[len(fields)-1])
+	}
+
+	want := []string{"added Running", "added Error", "changed Running"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// Values for --color that set when the output is colorized, instead of
+// adding a rule.
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// noColorEnvVar disables colors when set to any value, unless they're
+// forced with --color always. See https://no-color.org.
+const noColorEnvVar = "NO_COLOR"
+
+// Styles used in colorized output: headerStyle for the column titles, and
+// highlightStart, using reverse video, for the cells that need attention,
+// like the ones changed since the previous --watch run.
+const (
+	headerStyle    = "\033[1m"
+	highlightStart = "\033[7m"
+)
+
+// resolveColors splits the --color values into the rules to apply and when
+// to apply them, and decides whether the output is colorized. By default,
+// it is only when writing to a terminal and NO_COLOR is not set.
+func resolveColors(opts *settings) error {
+	mode := colorAuto
+	opts.colorRules = nil
+
+	for _, v := range opts.colors {
+		switch v {
+		case colorAuto, colorAlways, colorNever:
+			mode = v
+			continue
+		}
+
+		rule, err := tabloid.ParseColorRule(v)
+		if err != nil {
+			return fmt.Errorf("invalid value for --color: %w", err)
+		}
+
+		opts.colorRules = append(opts.colorRules, rule)
+	}
+
+	switch mode {
+	case colorAlways:
+		opts.useColor = true
+	case colorNever:
+		opts.useColor = false
+	default:
+		opts.useColor = os.Getenv(noColorEnvVar) == "" && isTerminal(os.Stdout)
+	}
+
+	return nil
+}
+
+// colorize styles the cells matching the color rules and, if requested, the
+// text matched by the regular expressions in the filter expression. It
+// must be called before selecting the columns to display, so rules can use
+// any of them.
+func colorize(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) ([]tabloid.Column, error) {
+	if !opts.useColor || (opts.output != outputTable && opts.output != "") {
+		return cols, nil
+	}
+
+	if opts.highlightMatches {
+		highlighted, err := tab.HighlightMatches(cols, opts.expr)
+		if err != nil {
+			return nil, err
+		}
+		cols = highlighted
+	}
+
+	return tab.Colorize(cols, opts.colorRules)
+}
+
+// boldTitles returns a decorator that prints the column titles in bold, if
+// the output is colorized, or nil otherwise.
+func boldTitles(opts settings) cellDecorator {
+	if !opts.useColor {
+		return nil
+	}
+
+	return func(row, col int, text string) string {
+		if row != headerRow {
+			return text
+		}
+
+		return headerStyle + text + tabloid.ANSIReset
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// countTitle is the title of the column added by --distinct-count.
+const countTitle = "COUNT"
+
+// resolveDistinct validates the flags removing duplicated rows and parses
+// which of them to keep.
+func resolveDistinct(opts *settings) error {
+	keep, err := tabloid.ParseDistinctKeep(opts.distinctKeep)
+	if err != nil {
+		return fmt.Errorf("invalid value for --distinct-keep: %w", err)
+	}
+	opts.keep = keep
+
+	if opts.distinct && len(opts.distinctBy) > 0 {
+		return fmt.Errorf("cannot use --distinct with --distinct-by")
+	}
+
+	if !removesDuplicates(*opts) {
+		if opts.distinctCount {
+			return fmt.Errorf("cannot use --distinct-count without --distinct or --distinct-by")
+		}
+		return nil
+	}
+
+	if opts.interactive {
+		return fmt.Errorf("cannot use --interactive with --distinct or --distinct-by")
+	}
+
+	if opts.changes {
+		return fmt.Errorf("cannot use --distinct or --distinct-by with --changes, since rows are already identified by their --key columns")
+	}
+
+	return nil
+}
+
+// removesDuplicates reports whether --distinct or --distinct-by was given.
+func removesDuplicates(opts settings) bool {
+	return opts.distinct || len(opts.distinctBy) > 0
+}
+
+// distinctRows removes the duplicated rows from the given rows, comparing
+// the output columns with --distinct, or the --distinct-by columns, which
+// are taken from the source columns so they don't need to be displayed. All
+// of them must have the same rows, and the rows are usually the output
+// columns themselves.
+func distinctRows(tab *tabloid.Tabloid, rows, output, source []tabloid.Column, opts settings) ([]tabloid.Column, error) {
+	if !removesDuplicates(opts) {
+		return rows, nil
+	}
+
+	keys := output
+	if len(opts.distinctBy) > 0 {
+		selected, err := tab.Select(source, opts.distinctBy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --distinct-by: %w", err)
+		}
+		keys = selected
+	}
+
+	title := ""
+	if opts.distinctCount {
+		title = countTitle
+	}
+
+	return tab.Distinct(rows, keys, opts.keep, title)
+}
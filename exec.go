@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// execJob is a command to run for a single row.
+type execJob struct {
+	row  int
+	args []string
+}
+
+// execResult is the outcome of running an execJob.
+type execResult struct {
+	job execJob
+	err error
+}
+
+// runExec runs the --exec command once per row, replacing the template
+// placeholders in it with the row values, then prints a summary of the
+// results.
+func runExec(w, errw io.Writer, cols []tabloid.Column, opts settings) error {
+	jobs, err := buildExecJobs(cols, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		for _, job := range jobs {
+			fmt.Fprintln(w, quoteArgs(job.args))
+		}
+		return nil
+	}
+
+	if opts.confirm {
+		jobs, err = confirmJobs(errw, jobs)
+		if err != nil {
+			return err
+		}
+	}
+
+	results := runJobs(w, errw, jobs, opts.parallel)
+
+	var failed []execResult
+	for _, result := range results {
+		if result.err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	fmt.Fprintf(errw, "exec: %d commands run, %d succeeded, %d failed\n", len(results), len(results)-len(failed), len(failed))
+	for _, result := range failed {
+		fmt.Fprintf(errw, "  row %d: %s: %s\n", result.job.row, quoteArgs(result.job.args), result.err)
+	}
+
+	if len(failed) > 0 {
+		return &exitError{code: exitCodeError}
+	}
+
+	return nil
+}
+
+// buildExecJobs renders the command for every row. Unless a shell was
+// requested, the command is split into arguments before rendering, so row
+// values are always passed as a single argument, no matter their contents.
+func buildExecJobs(cols []tabloid.Column, opts settings) ([]execJob, error) {
+	words := []string{opts.exec}
+	if !opts.shell {
+		var err error
+		if words, err = splitArgs(opts.exec); err != nil {
+			return nil, fmt.Errorf("invalid value for --exec: %w", err)
+		}
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("invalid value for --exec: no command given")
+	}
+
+	templates := make([]*template.Template, 0, len(words))
+	for _, word := range words {
+		tmpl, err := template.New("exec").Funcs(template.FuncMap{"quote": shellQuote}).Option("missingkey=error").Parse(word)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in --exec: %w", err)
+		}
+		templates = append(templates, tmpl)
+	}
+
+	jobs := make([]execJob, 0, rowsIn(cols))
+	for i := 0; i < rowsIn(cols); i++ {
+		data := make(map[string]string, len(cols))
+		for _, c := range cols {
+			data[c.ExprTitle] = c.Values[i]
+		}
+
+		args := make([]string, 0, len(templates))
+		for _, tmpl := range templates {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, data); err != nil {
+				return nil, fmt.Errorf("unable to render --exec command for row %d: %w", i+1, err)
+			}
+			args = append(args, b.String())
+		}
+
+		if opts.shell {
+			args = append(shellCommand(), args[0])
+		}
+
+		jobs = append(jobs, execJob{row: i + 1, args: args})
+	}
+
+	return jobs, nil
+}
+
+// confirmJobs asks, using the terminal, whether each command should be run,
+// returning only the confirmed ones.
+func confirmJobs(errw io.Writer, jobs []execJob) ([]execJob, error) {
+	tty, err := openTerminal()
+	if err != nil {
+		return nil, fmt.Errorf("unable to ask for confirmation: %w", err)
+	}
+	defer tty.Close()
+
+	reader := bufio.NewReader(tty)
+	confirmed := make([]execJob, 0, len(jobs))
+
+	for _, job := range jobs {
+		fmt.Fprintf(errw, "run %s? [y/N] ", quoteArgs(job.args))
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("unable to read confirmation: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			confirmed = append(confirmed, job)
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	return confirmed, nil
+}
+
+// runJobs runs the jobs using the given amount of workers. With a single
+// worker, commands write directly to the outputs; otherwise, their standard
+// output and error are buffered and printed once they finish, so they don't
+// get interleaved.
+func runJobs(w, errw io.Writer, jobs []execJob, workers int) []execResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make([]execResult, len(jobs))
+		queue   = make(chan int)
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for pos := range queue {
+				result := execResult{job: jobs[pos]}
+
+				cmd := exec.Command(jobs[pos].args[0], jobs[pos].args[1:]...)
+
+				var stdout, stderr bytes.Buffer
+				if workers == 1 {
+					cmd.Stdout, cmd.Stderr = w, errw
+				} else {
+					cmd.Stdout, cmd.Stderr = &stdout, &stderr
+				}
+
+				result.err = cmd.Run()
+
+				if stdout.Len() > 0 || stderr.Len() > 0 {
+					mu.Lock()
+					w.Write(stdout.Bytes())
+					errw.Write(stderr.Bytes())
+					mu.Unlock()
+				}
+
+				results[pos] = result
+			}
+		}()
+	}
+
+	for pos := range jobs {
+		queue <- pos
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// splitArgs splits a command into its arguments the way a POSIX shell
+// would, honoring single quotes, double quotes and backslashes, but without
+// any expansions. Template actions, like {{ index . "name" }}, are kept
+// as they are, so they can contain spaces and quotes.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for i := 0; i < len(s); {
+		if !escaped && strings.HasPrefix(s[i:], "{{") {
+			end := strings.Index(s[i:], "}}")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated template action in %q", s)
+			}
+
+			current.WriteString(s[i : i+end+2])
+			inWord = true
+			i += end + 2
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("unterminated escape sequence in %q", s)
+	}
+
+	if inWord {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// shellQuote quotes a string so a POSIX shell treats it as a single word.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+	}) == -1 {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteArgs joins the arguments, quoting them when needed, to display them
+// as a command line.
+func quoteArgs(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// shellCommand returns the command used to run --exec through a shell.
+func shellCommand() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C"}
+	}
+
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return []string{shell, "-c"}
+	}
+
+	return []string{"sh", "-c"}
+}
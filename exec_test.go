@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+func Test_splitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "words", input: "kubectl  delete pod", want: []string{"kubectl", "delete", "pod"}},
+		{name: "quotes", input: `echo 'a b' "c d" e\ f`, want: []string{"echo", "a b", "c d", "e f"}},
+		{name: "single quotes keep backslashes", input: `echo 'a\b'`, want: []string{"echo", `a\b`}},
+		{name: "empty quotes", input: `echo ""`, want: []string{"echo", ""}},
+		{
+			name:  "template actions with spaces",
+			input: "kubectl delete pod {{ .name }} -n {{ .namespace }}",
+			want:  []string{"kubectl", "delete", "pod", "{{ .name }}", "-n", "{{ .namespace }}"},
+		},
+		{
+			name:  "template actions with quotes",
+			input: `echo {{index . "name"}} "pod {{ index . "name" }}"`,
+			want:  []string{"echo", `{{index . "name"}}`, `pod {{ index . "name" }}`},
+		},
+		{name: "unterminated quote", input: `echo 'a`, wantErr: true},
+		{name: "unterminated escape", input: `echo a\`, wantErr: true},
+		{name: "unterminated action", input: `echo {{ .name`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitArgs(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_shellQuote(t *testing.T) {
+	tests := map[string]string{
+		"web-1":     "web-1",
+		"":          "''",
+		"a b":       "'a b'",
+		"it's":      `'it'\''s'`,
+		"$(rm -rf)": "'$(rm -rf)'",
+	}
+	for input, want := range tests {
+		if got := shellQuote(input); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func Test_buildExecJobs(t *testing.T) {
+	cols := []tabloid.Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "bad; rm -rf /"}},
+		{Title: "NAMESPACE", ExprTitle: "namespace", Values: []string{"default", "kube system"}},
+	}
+
+	tests := []struct {
+		name    string
+		opts    settings
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name: "values are single arguments",
+			opts: settings{exec: "kubectl delete pod {{ .name }} -n {{ .namespace }}"},
+			want: [][]string{
+				{"kubectl", "delete", "pod", "web-1", "-n", "default"},
+				{"kubectl", "delete", "pod", "bad; rm -rf /", "-n", "kube system"},
+			},
+		},
+		{
+			name: "index function",
+			opts: settings{exec: `echo {{index . "name"}}`},
+			want: [][]string{{"echo", "web-1"}, {"echo", "bad; rm -rf /"}},
+		},
+		{
+			name: "shell",
+			opts: settings{exec: "echo {{quote .name}}", shell: true},
+			want: [][]string{
+				append(shellCommand(), "echo web-1"),
+				append(shellCommand(), "echo 'bad; rm -rf /'"),
+			},
+		},
+		{name: "unknown column", opts: settings{exec: "echo {{.image}}"}, wantErr: true},
+		{name: "invalid template", opts: settings{exec: "echo {{.name"}, wantErr: true},
+		{name: "no command", opts: settings{exec: "  "}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := buildExecJobs(cols, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got := make([][]string, 0, len(jobs))
+			for _, job := range jobs {
+				got = append(got, job.args)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runExec_failedCommand(t *testing.T) {
+	cols := []tabloid.Column{{Title: "NAME", ExprTitle: "name", Values: []string{"web-1"}}}
+
+	var out, errOut bytes.Buffer
+	err := runExec(&out, &errOut, cols, settings{exec: "false", parallel: 1})
+
+	exitErr, ok := err.(*exitError)
+	if !ok || exitErr.code != exitCodeError {
+		t.Errorf("error = %v, want exit code %d", err, exitCodeError)
+	}
+
+	if !strings.Contains(errOut.String(), "1 failed") {
+		t.Errorf("summary = %q, want one failed command", errOut.String())
+	}
+}
+
+func Test_runJobs_parallelOutputs(t *testing.T) {
+	jobs := []execJob{
+		{row: 1, args: []string{"sh", "-c", "echo out; echo err >&2"}},
+		{row: 2, args: []string{"sh", "-c", "echo out; echo err >&2"}},
+	}
+
+	var out, errOut bytes.Buffer
+	runJobs(&out, &errOut, jobs, 2)
+
+	if got := out.String(); got != "out\nout\n" {
+		t.Errorf("stdout = %q, want only the standard output of the commands", got)
+	}
+
+	if got := errOut.String(); got != "err\nerr\n" {
+		t.Errorf("stderr = %q, want only the standard error of the commands", got)
+	}
+}
+
+func Test_run_execDistinct(t *testing.T) {
+	input := podsInput + "default     web-3   1/1     Running   0          2d\n"
+
+	tests := []struct {
+		name       string
+		columns    []string
+		distinctBy []string
+		want       string
+	}{
+		{name: "displayed columns", columns: []string{"status"}, want: "web-1 Running\nweb-2 Error\n"},
+		{name: "whole rows", want: "web-1 Running\nweb-2 Error\nweb-3 Running\n"},
+		{name: "distinct by", distinctBy: []string{"ready"}, want: "web-1 Running\nweb-2 Error\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSettings()
+			opts.exec = "echo {{.name}} {{.status}}"
+			opts.parallel = 1
+			opts.distinct = len(tt.distinctBy) == 0
+			opts.distinctBy = tt.distinctBy
+			opts.columns = tt.columns
+			opts.keep = tabloid.DistinctFirst
+
+			var out bytes.Buffer
+			if err := run(strings.NewReader(input), &out, opts); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
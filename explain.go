@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// printExplanations writes every row with the result of the expression, its
+// values and each one of the evaluated sub-expressions and function calls.
+func printExplanations(w io.Writer, cols []tabloid.Column, explanations []tabloid.Explanation) {
+	for _, e := range explanations {
+		if e.Err != nil {
+			fmt.Fprintf(w, "row %d: error: %s\n", e.Row, e.Err)
+		} else {
+			fmt.Fprintf(w, "row %d: %t\n", e.Row, e.Result)
+		}
+
+		values := make([]string, 0, len(cols))
+		for _, c := range cols {
+			if s, ok := e.Values[c.ExprTitle].(string); ok {
+				values = append(values, fmt.Sprintf("%s=%q", c.ExprTitle, s))
+				continue
+			}
+			values = append(values, fmt.Sprintf("%s=%v", c.ExprTitle, e.Values[c.ExprTitle]))
+		}
+		fmt.Fprintf(w, "    %s\n", strings.Join(values, " "))
+
+		for _, step := range e.Steps {
+			fmt.Fprintf(w, "    %s%s\n", strings.Repeat("    ", step.Depth), step)
+		}
+	}
+}
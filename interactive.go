@@ -0,0 +1,596 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+const (
+	enterAltScreen = "\033[?1049h\033[?1000h\033[?1006h"
+	leaveAltScreen = "\033[?1006l\033[?1000l\033[?1049l"
+	clearLine      = "\033[K"
+	clearBelow     = "\033[J"
+	styleDim       = "\033[2m"
+	styleUnderline = "\033[4m"
+	styleRed       = "\033[31m"
+)
+
+// Lines used by the interactive mode before the table starts.
+const (
+	lineExpression = iota + 1
+	lineStatus
+	lineColumns
+	lineTable
+)
+
+const interactiveHelp = "←/→ column · ^X show/hide · ^S sort · ↑/↓ scroll · enter print · esc quit"
+
+// interactive holds the state of the interactive mode: the expression being
+// typed, which columns are visible and how the rows are sorted.
+type interactive struct {
+	tab  *tabloid.Tabloid
+	cols []tabloid.Column
+	opts settings
+
+	expr     string
+	visible  []bool
+	current  int
+	sortBy   int
+	sortDesc bool
+	offset   int
+
+	result []tabloid.Column
+	err    error
+
+	width, height int
+	columnsBar    [][2]int
+	headerRanges  [][2]int
+	headerColumns []int
+}
+
+// runInteractive opens a full-screen view over the parsed columns where the
+// expression is re-evaluated as it's typed. Once accepted, the visible rows
+// and columns are printed to the given writer.
+func runInteractive(w io.Writer, tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) error {
+	tty, err := openTerminal()
+	if err != nil {
+		return fmt.Errorf("interactive mode requires a terminal: %w", err)
+	}
+	defer tty.Close()
+
+	restore, err := makeRaw(tty)
+	if err != nil {
+		return fmt.Errorf("interactive mode requires a terminal: %w", err)
+	}
+
+	ui := &interactive{tab: tab, cols: cols, opts: opts, expr: opts.expr, sortBy: -1}
+
+	// The screen is cut to the terminal width while rendering, and
+	// scrolled horizontally, so tables are not fitted to it. Since titles
+	// can be clicked, tables are always drawn with the default style.
+	ui.opts.tableWidth = 0
+	ui.opts.table = defaultStyle
+	ui.opts.separator = ""
+	ui.opts.repeatTitles = 0
+	if err := ui.init(); err != nil {
+		restore()
+		return err
+	}
+
+	fmt.Fprint(tty, enterAltScreen)
+	accepted, err := ui.loop(tty)
+	fmt.Fprint(tty, leaveAltScreen)
+
+	if rerr := restore(); rerr != nil && err == nil {
+		err = fmt.Errorf("unable to restore terminal: %w", rerr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !accepted {
+		return &exitError{code: exitCodeCancelled}
+	}
+
+	return printOutput(w, ui.view(), opts)
+}
+
+// init sets the initially visible columns and sorting, based on --column
+// and --sort, and evaluates the initial expression.
+func (ui *interactive) init() error {
+	ui.visible = make([]bool, len(ui.cols))
+
+	selected, err := ui.tab.Select(ui.cols, ui.opts.columns)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range selected {
+		for pos, c := range ui.cols {
+			if c.ExprTitle == s.ExprTitle {
+				ui.visible[pos] = true
+			}
+		}
+	}
+
+	if ui.opts.sort != "" {
+		sorted, err := ui.tab.Select(ui.cols, []string{ui.opts.sort})
+		if err != nil {
+			return err
+		}
+
+		for pos, c := range ui.cols {
+			if c.ExprTitle == sorted[0].ExprTitle {
+				ui.sortBy, ui.sortDesc = pos, ui.opts.sortDesc
+			}
+		}
+	}
+
+	ui.result = ui.cols
+	ui.evaluate()
+	return nil
+}
+
+// loop reads keys from the terminal until the selection is accepted or
+// cancelled. The size of the terminal is read once, and again only when
+// it's resized.
+func (ui *interactive) loop(tty *os.File) (bool, error) {
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	if err := ui.resize(tty); err != nil {
+		return false, err
+	}
+
+	var (
+		input  = make(chan []byte)
+		failed = make(chan error, 1)
+		done   = make(chan struct{})
+	)
+	defer close(done)
+	go readTerminal(tty, input, failed, done)
+
+	for {
+		if err := ui.render(tty); err != nil {
+			return false, err
+		}
+
+		select {
+		case <-resized:
+			if err := ui.resize(tty); err != nil {
+				return false, err
+			}
+		case err := <-failed:
+			return false, fmt.Errorf("unable to read from terminal: %w", err)
+		case b := <-input:
+			for _, key := range parseKeys(b) {
+				switch key.name {
+				case "enter":
+					return true, nil
+				case "esc", "ctrl-c":
+					return false, nil
+				default:
+					ui.handle(key)
+				}
+			}
+		}
+	}
+}
+
+// readTerminal sends what's read from the terminal to the input channel,
+// until reading fails or done is closed.
+func readTerminal(tty *os.File, input chan<- []byte, failed chan<- error, done <-chan struct{}) {
+	for {
+		buf := make([]byte, 256)
+
+		n, err := tty.Read(buf)
+		if err != nil {
+			failed <- err
+			return
+		}
+
+		select {
+		case input <- buf[:n]:
+		case <-done:
+			return
+		}
+	}
+}
+
+// resize reads the size of the terminal.
+func (ui *interactive) resize(tty *os.File) error {
+	width, height, err := terminalSize(tty)
+	if err != nil {
+		return err
+	}
+
+	ui.width, ui.height = width, height
+	return nil
+}
+
+// handle applies a single key press to the state.
+func (ui *interactive) handle(key keyPress) {
+	pageSize := ui.height - lineTable
+	if pageSize < 1 {
+		pageSize = 1
+	}
+
+	switch key.name {
+	case "text":
+		ui.expr += key.text
+		ui.evaluate()
+	case "backspace":
+		if ui.expr != "" {
+			_, size := utf8.DecodeLastRuneInString(ui.expr)
+			ui.expr = ui.expr[:len(ui.expr)-size]
+			ui.evaluate()
+		}
+	case "ctrl-u":
+		ui.expr = ""
+		ui.evaluate()
+	case "left":
+		if ui.current > 0 {
+			ui.current--
+		}
+	case "right":
+		if ui.current < len(ui.cols)-1 {
+			ui.current++
+		}
+	case "ctrl-x":
+		ui.visible[ui.current] = !ui.visible[ui.current]
+	case "ctrl-s":
+		ui.toggleSort(ui.current)
+	case "up":
+		ui.scroll(-1)
+	case "down":
+		ui.scroll(1)
+	case "pgup":
+		ui.scroll(-pageSize)
+	case "pgdown":
+		ui.scroll(pageSize)
+	case "wheelup":
+		ui.scroll(-3)
+	case "wheeldown":
+		ui.scroll(3)
+	case "click":
+		ui.click(key.x, key.y)
+	}
+}
+
+// click selects the column under the cursor: clicking a column in the
+// columns bar shows or hides it, while clicking a table title sorts by it.
+func (ui *interactive) click(x, y int) {
+	ranges, columns := ui.columnsBar, []int(nil)
+	switch y {
+	case lineColumns:
+	case lineTable:
+		ranges, columns = ui.headerRanges, ui.headerColumns
+	default:
+		return
+	}
+
+	for i, r := range ranges {
+		if x < r[0] || x >= r[1] {
+			continue
+		}
+
+		pos := i
+		if columns != nil {
+			pos = columns[i]
+		}
+
+		ui.current = pos
+		if y == lineColumns {
+			ui.visible[pos] = !ui.visible[pos]
+		} else {
+			ui.toggleSort(pos)
+		}
+		return
+	}
+}
+
+// toggleSort cycles the sorting of a column between ascending, descending
+// and unsorted.
+func (ui *interactive) toggleSort(pos int) {
+	switch {
+	case ui.sortBy != pos:
+		ui.sortBy, ui.sortDesc = pos, false
+	case !ui.sortDesc:
+		ui.sortDesc = true
+	default:
+		ui.sortBy, ui.sortDesc = -1, false
+	}
+
+	ui.evaluate()
+}
+
+func (ui *interactive) scroll(delta int) {
+	ui.offset += delta
+
+	if max := rowsIn(ui.result) - 1; ui.offset > max {
+		ui.offset = max
+	}
+
+	if ui.offset < 0 {
+		ui.offset = 0
+	}
+}
+
+// evaluate filters and sorts the rows using the current state. If the
+// expression is invalid, the error is kept to be displayed along with the
+// rows from the last valid expression.
+func (ui *interactive) evaluate() {
+	result, err := ui.tab.Filter(ui.cols, ui.expr)
+	if err == nil && ui.sortBy >= 0 {
+		result, err = ui.tab.Sort(result, ui.cols[ui.sortBy].ExprTitle, ui.sortDesc)
+	}
+
+	ui.err = err
+	if err != nil {
+		return
+	}
+
+	ui.result = result
+	ui.scroll(0)
+}
+
+// view returns the visible columns of the current result.
+func (ui *interactive) view() []tabloid.Column {
+	view := make([]tabloid.Column, 0, len(ui.result))
+	for pos, c := range ui.result {
+		if ui.visible[pos] {
+			view = append(view, c)
+		}
+	}
+
+	return view
+}
+
+// render draws the whole screen, followed by placing the cursor at the end
+// of the expression.
+func (ui *interactive) render(tty *os.File) error {
+	width, height := ui.width, ui.height
+
+	var screen bytes.Buffer
+	line := func(s string) {
+		screen.WriteString(truncateANSI(s, width))
+		screen.WriteString(clearLine + "\r\n")
+	}
+
+	screen.WriteString("\033[H")
+
+	prompt := "expr> "
+	line(prompt + ui.expr)
+
+	if ui.err != nil {
+		line(styleRed + "error: " + ui.err.Error() + tabloid.ANSIReset)
+	} else {
+		status := fmt.Sprintf("%d/%d rows", rowsIn(ui.result), rowsIn(ui.cols))
+		if ui.sortBy >= 0 {
+			direction := "asc"
+			if ui.sortDesc {
+				direction = "desc"
+			}
+			status += fmt.Sprintf(" · sorted by %s %s", ui.cols[ui.sortBy].Title, direction)
+		}
+		line(styleDim + status + " · " + interactiveHelp + tabloid.ANSIReset)
+	}
+
+	line(ui.renderColumnsBar())
+
+	var table bytes.Buffer
+	view := ui.view()
+	if len(view) > 0 {
+		if _, err := writeTable(&table, view, ui.opts, chainDecorators(styledCells(view), ui.decorateHeader(view))); err != nil {
+			return err
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	available := height - lineTable
+	if len(view) > 0 && !ui.opts.noTitles {
+		line(lines[0])
+		lines = lines[1:]
+	} else {
+		available++
+	}
+
+	start := ui.offset
+	if start > len(lines) {
+		start = len(lines)
+	}
+
+	for i := start; i < len(lines) && i-start < available; i++ {
+		line(lines[i])
+	}
+
+	screen.WriteString(clearBelow)
+	screen.WriteString(fmt.Sprintf("\033[%d;%dH", lineExpression, utf8.RuneCountInString(prompt+ui.expr)+1))
+
+	_, err := tty.Write(screen.Bytes())
+	return err
+}
+
+// renderColumnsBar lists all columns, dimming the hidden ones and
+// highlighting the current one, while recording where each one of them is
+// so they can be clicked.
+func (ui *interactive) renderColumnsBar() string {
+	var (
+		b strings.Builder
+		x = 1
+	)
+
+	ui.columnsBar = ui.columnsBar[:0]
+	for pos, c := range ui.cols {
+		label := c.Title
+		if !ui.visible[pos] {
+			label = "(" + label + ")"
+		}
+
+		style := ""
+		if !ui.visible[pos] {
+			style += styleDim
+		}
+		if pos == ui.current {
+			style += highlightStart
+		}
+
+		ui.columnsBar = append(ui.columnsBar, [2]int{x, x + utf8.RuneCountInString(label)})
+		x += utf8.RuneCountInString(label) + 2
+
+		b.WriteString(style + label + tabloid.ANSIReset + "  ")
+	}
+
+	return b.String()
+}
+
+// decorateHeader returns a decorator that highlights the current column and
+// underlines the sorted one, while recording where each title is so they
+// can be clicked.
+func (ui *interactive) decorateHeader(view []tabloid.Column) cellDecorator {
+	positions := make([]int, 0, len(view))
+	for pos := range ui.result {
+		if ui.visible[pos] {
+			positions = append(positions, pos)
+		}
+	}
+
+	titles := tableTitles(view, ui.opts)
+	widths := fitTable(view, titles, ui.opts).widths
+
+	ui.headerRanges, ui.headerColumns = ui.headerRanges[:0], positions
+	x := 1
+	for _, width := range widths {
+		ui.headerRanges = append(ui.headerRanges, [2]int{x, x + width + cellPadding})
+		x += width + cellPadding
+	}
+
+	return func(row, col int, text string) string {
+		if row != headerRow {
+			return text
+		}
+
+		style := headerStyle
+		if positions[col] == ui.current {
+			style += highlightStart
+		}
+		if positions[col] == ui.sortBy {
+			style += styleUnderline
+		}
+
+		return style + text + tabloid.ANSIReset
+	}
+}
+
+// keyPress is a single key, or mouse event, read from the terminal.
+type keyPress struct {
+	name string
+	text string
+	x, y int
+}
+
+// parseKeys converts the bytes read from a terminal in raw mode into key
+// presses, including arrow keys and mouse clicks reported in SGR mode.
+func parseKeys(b []byte) []keyPress {
+	var keys []keyPress
+
+	for i := 0; i < len(b); {
+		c := b[i]
+
+		switch {
+		case c == 0x1b && i+1 < len(b) && (b[i+1] == '[' || b[i+1] == 'O'):
+			end := i + 2
+			for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+				end++
+			}
+			if end >= len(b) {
+				return keys
+			}
+
+			if key, ok := parseSequence(string(b[i+2:end]), b[end]); ok {
+				keys = append(keys, key)
+			}
+			i = end + 1
+		case c == 0x1b:
+			keys = append(keys, keyPress{name: "esc"})
+			i++
+		case c == 0x03:
+			keys = append(keys, keyPress{name: "ctrl-c"})
+			i++
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyPress{name: "enter"})
+			i++
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, keyPress{name: "backspace"})
+			i++
+		case c == 0x15:
+			keys = append(keys, keyPress{name: "ctrl-u"})
+			i++
+		case c == 0x18:
+			keys = append(keys, keyPress{name: "ctrl-x"})
+			i++
+		case c == 0x13:
+			keys = append(keys, keyPress{name: "ctrl-s"})
+			i++
+		case c < 0x20:
+			i++
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			if r != utf8.RuneError {
+				keys = append(keys, keyPress{name: "text", text: string(r)})
+			}
+			i += size
+		}
+	}
+
+	return keys
+}
+
+// parseSequence converts the parameters and final byte of an escape
+// sequence into a key press.
+func parseSequence(params string, final byte) (keyPress, bool) {
+	switch {
+	case final == 'A':
+		return keyPress{name: "up"}, true
+	case final == 'B':
+		return keyPress{name: "down"}, true
+	case final == 'C':
+		return keyPress{name: "right"}, true
+	case final == 'D':
+		return keyPress{name: "left"}, true
+	case final == '~' && params == "5":
+		return keyPress{name: "pgup"}, true
+	case final == '~' && params == "6":
+		return keyPress{name: "pgdown"}, true
+	case final == 'M' && strings.HasPrefix(params, "<"):
+		fields := strings.Split(params[1:], ";")
+		if len(fields) != 3 {
+			return keyPress{}, false
+		}
+
+		button, _ := strconv.Atoi(fields[0])
+		x, _ := strconv.Atoi(fields[1])
+		y, _ := strconv.Atoi(fields[2])
+
+		switch button {
+		case 0:
+			return keyPress{name: "click", x: x, y: y}, true
+		case 64:
+			return keyPress{name: "wheelup"}, true
+		case 65:
+			return keyPress{name: "wheeldown"}, true
+		}
+	}
+
+	return keyPress{}, false
+}
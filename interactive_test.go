@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []keyPress
+	}{
+		{
+			name:  "text",
+			input: "ná",
+			want:  []keyPress{{name: "text", text: "n"}, {name: "text", text: "á"}},
+		},
+		{
+			name:  "control keys",
+			input: "\r\x7f\x15\x18\x13\x03",
+			want: []keyPress{
+				{name: "enter"}, {name: "backspace"}, {name: "ctrl-u"},
+				{name: "ctrl-x"}, {name: "ctrl-s"}, {name: "ctrl-c"},
+			},
+		},
+		{
+			name:  "escape alone",
+			input: "\x1b",
+			want:  []keyPress{{name: "esc"}},
+		},
+		{
+			name:  "arrows in both modes",
+			input: "\x1b[A\x1bOB\x1b[C\x1b[D",
+			want:  []keyPress{{name: "up"}, {name: "down"}, {name: "right"}, {name: "left"}},
+		},
+		{
+			name:  "mouse click between text",
+			input: "a\x1b[<0;12;4Mb",
+			want:  []keyPress{{name: "text", text: "a"}, {name: "click", x: 12, y: 4}, {name: "text", text: "b"}},
+		},
+		{
+			name:  "unknown sequences and control characters are ignored",
+			input: "\x1b[2~\x01a",
+			want:  []keyPress{{name: "text", text: "a"}},
+		},
+		{
+			name:  "incomplete sequence",
+			input: "a\x1b[<0;1",
+			want:  []keyPress{{name: "text", text: "a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeys(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseSequence(t *testing.T) {
+	tests := []struct {
+		params string
+		final  byte
+		want   keyPress
+		wantOK bool
+	}{
+		{params: "5", final: '~', want: keyPress{name: "pgup"}, wantOK: true},
+		{params: "6", final: '~', want: keyPress{name: "pgdown"}, wantOK: true},
+		{params: "3", final: '~'},
+		{params: "<0;3;2", final: 'M', want: keyPress{name: "click", x: 3, y: 2}, wantOK: true},
+		{params: "<64;3;2", final: 'M', want: keyPress{name: "wheelup"}, wantOK: true},
+		{params: "<65;3;2", final: 'M', want: keyPress{name: "wheeldown"}, wantOK: true},
+		{params: "<2;3;2", final: 'M'},
+		{params: "<0;3", final: 'M'},
+		{params: "", final: 'Z'},
+	}
+	for _, tt := range tests {
+		got, ok := parseSequence(tt.params, tt.final)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseSequence(%q, %q) = %+v, %v, want %+v, %v", tt.params, tt.final, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// ellipsis marks the cells cut to fit their column.
+const ellipsis = "…"
+
+// minColumnWidth is the narrowest a column is shrunk to fit the table in the
+// available width, unless its values are narrower.
+const minColumnWidth = 8
+
+// Special values for --width.
+const (
+	widthAuto      = 0
+	widthUnlimited = -1
+)
+
+// layout is how a table fits in the available width: the positions of the
+// columns displayed, in order, and the width of every column.
+type layout struct {
+	columns []int
+	widths  []int
+
+	// fitted is set when the table was fitted to a width, in which case the
+	// padding after the last column is left out so lines don't overflow.
+	fitted bool
+}
+
+// hidden returns the titles of the columns left out of the layout.
+func (l layout) hidden(output []tabloid.Column) []string {
+	shown := make(map[int]bool, len(l.columns))
+	for _, col := range l.columns {
+		shown[col] = true
+	}
+
+	var hidden []string
+	for col, c := range output {
+		if !shown[col] {
+			hidden = append(hidden, c.Title)
+		}
+	}
+
+	return hidden
+}
+
+// cellLine is a line of a cell, along with its visible width.
+type cellLine struct {
+	text  string
+	width int
+}
+
+// resolveLayout parses the --max-width values and decides the width to fit
+// tables in. By default, it's the width of the terminal when writing to
+// one, taken from $COLUMNS or the terminal itself, and unlimited otherwise.
+func resolveLayout(opts *settings) error {
+	opts.columnMaxWidths = make(map[string]int, len(opts.maxWidths))
+
+	for _, v := range opts.maxWidths {
+		name, value := "", v
+		if pos := strings.LastIndex(v, "="); pos != -1 {
+			name, value = strings.TrimSpace(v[:pos]), v[pos+1:]
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value for --max-width: %q must be a width of at least 1, optionally preceded by a column, like name=40", v)
+		}
+
+		opts.columnMaxWidths[name] = n
+	}
+
+	switch {
+	case opts.width == widthUnlimited:
+		opts.tableWidth = 0
+	case opts.width > 0:
+		opts.tableWidth = opts.width
+	case opts.width == widthAuto:
+		opts.tableWidth = detectWidth()
+	default:
+		return fmt.Errorf("invalid value for --width: must be %d to detect it, %d for no limit, or a positive width, got %d", widthAuto, widthUnlimited, opts.width)
+	}
+
+	return nil
+}
+
+// detectWidth returns the width of the terminal the output is written to,
+// or 0 if it's not a terminal or its width is unknown. The terminal is only
+// asked for its size when $COLUMNS isn't set, and never when the output
+// isn't a terminal, since that runs a command.
+func detectWidth() int {
+	if !isTerminal(os.Stdout) {
+		return 0
+	}
+
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+
+	width, _, err := terminalSize(os.Stdout)
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// maxWidthFor returns the maximum width of a column, either its own or the
+// one set for all columns, or 0 if it has none.
+func maxWidthFor(column tabloid.Column, opts settings) int {
+	for name, n := range opts.columnMaxWidths {
+		if name != "" && isColumn(column, name) {
+			return n
+		}
+	}
+
+	return opts.columnMaxWidths[""]
+}
+
+// isColumn reports whether a name refers to the column, by its title or
+// its normalized title.
+func isColumn(column tabloid.Column, name string) bool {
+	return strings.EqualFold(name, column.Title) || name == column.ExprTitle
+}
+
+// dropSequence returns the positions of the columns in the order they're
+// dropped when the table doesn't fit: first the ones given in --drop-order,
+// in that order, then the rest from the last one.
+func dropSequence(output []tabloid.Column, opts settings) []int {
+	sequence := make([]int, 0, len(output))
+	listed := make(map[int]bool, len(opts.dropOrder))
+
+	for _, name := range opts.dropOrder {
+		for col, c := range output {
+			if !listed[col] && isColumn(c, strings.TrimSpace(name)) {
+				sequence = append(sequence, col)
+				listed[col] = true
+			}
+		}
+	}
+
+	for col := len(output) - 1; col >= 0; col-- {
+		if !listed[col] {
+			sequence = append(sequence, col)
+		}
+	}
+
+	return sequence
+}
+
+// fitTable decides the columns to display and their widths. Columns wider
+// than their maximum width are cut, and when the table doesn't fit in the
+// width available, columns are dropped, following the --drop-order ones
+// and then from the last one, until the rest can fit, and the widest ones
+// are shrunk until they do.
+func fitTable(output []tabloid.Column, titles []string, opts settings) layout {
+	widths := columnWidths(output, titles, opts)
+	columns := make([]int, 0, len(output))
+
+	for col := range output {
+		columns = append(columns, col)
+		if max := maxWidthFor(output[col], opts); max > 0 && widths[col] > max {
+			widths[col] = max
+		}
+	}
+
+	limit := opts.tableWidth
+	if limit <= 0 {
+		return layout{columns: columns, widths: widths}
+	}
+
+	total := func(minimum bool) int {
+		sum := opts.tableStyle().overhead(len(columns))
+		for _, col := range columns {
+			if minimum && widths[col] > minColumnWidth {
+				sum += minColumnWidth
+				continue
+			}
+			sum += widths[col]
+		}
+		return sum
+	}
+
+	for _, drop := range dropSequence(output, opts) {
+		if len(columns) == 1 || total(true) <= limit {
+			break
+		}
+
+		for i, col := range columns {
+			if col == drop {
+				columns = append(columns[:i], columns[i+1:]...)
+				break
+			}
+		}
+	}
+
+	for total(false) > limit {
+		widest := -1
+		for _, col := range columns {
+			if widths[col] > minColumnWidth && (widest == -1 || widths[col] > widths[widest]) {
+				widest = col
+			}
+		}
+
+		if widest == -1 {
+			break
+		}
+		widths[widest]--
+	}
+
+	// A single column wider than the limit is cut to fit.
+	if total(false) > limit {
+		widths[columns[0]] = limit
+	}
+
+	return layout{columns: columns, widths: widths, fitted: true}
+}
+
+// layoutCell splits a cell into the lines needed to display it in the
+// given width, either cutting it with an ellipsis or wrapping it. The
+// decorated text is the cell as it's printed, which may contain escape
+// sequences, and is cut at the same places as the text.
+func layoutCell(text, decorated string, width int, wrap bool) []cellLine {
+	n := utf8.RuneCountInString(text)
+	if n <= width {
+		return []cellLine{{text: decorated, width: n}}
+	}
+
+	if tabloid.StripANSI(decorated) != text {
+		decorated = text
+	}
+
+	if !wrap {
+		return []cellLine{{text: sliceANSI(decorated, 0, width-1) + ellipsis, width: width}}
+	}
+
+	var lines []cellLine
+	for _, r := range wrapRanges([]rune(text), width) {
+		lines = append(lines, cellLine{text: sliceANSI(decorated, r[0], r[1]), width: r[1] - r[0]})
+	}
+
+	return lines
+}
+
+// wrapRanges splits text into ranges no wider than the width, breaking lines
+// after the last space that fits, or in the middle of a word otherwise.
+// Spaces at the edges of every range are left out.
+func wrapRanges(text []rune, width int) [][2]int {
+	var ranges [][2]int
+
+	for start := 0; start < len(text); {
+		end := start + width
+		if end >= len(text) {
+			end = len(text)
+		} else {
+			for i := end; i > start; i-- {
+				if unicode.IsSpace(text[i]) {
+					end = i
+					break
+				}
+			}
+		}
+
+		trimmed := end
+		for trimmed > start && unicode.IsSpace(text[trimmed-1]) {
+			trimmed--
+		}
+		ranges = append(ranges, [2]int{start, trimmed})
+
+		start = end
+		for start < len(text) && unicode.IsSpace(text[start]) {
+			start++
+		}
+	}
+
+	return ranges
+}
+
+// sliceANSI returns the visible characters of a string between the given
+// positions, keeping all its escape sequences, so the slice keeps the
+// styles set before it, and resetting them at the end.
+func sliceANSI(s string, start, end int) string {
+	var (
+		b       strings.Builder
+		visible int
+	)
+
+	for i := 0; i < len(s); {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			next := i + 2
+			for next < len(s) && (s[next] < 0x40 || s[next] > 0x7e) {
+				next++
+			}
+			if next < len(s) {
+				next++
+			}
+
+			b.WriteString(s[i:next])
+			i = next
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if visible >= start && visible < end {
+			b.WriteRune(r)
+		}
+		visible++
+		i += size
+	}
+
+	if strings.Contains(s, "\033[") && !strings.HasSuffix(b.String(), tabloid.ANSIReset) {
+		b.WriteString(tabloid.ANSIReset)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+func Test_fitTable(t *testing.T) {
+	output := []tabloid.Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web-1"}},
+		{Title: "IMAGE", ExprTitle: "image", Values: []string{"registry.example.com/web:1.2.3"}},
+		{Title: "COMMAND", ExprTitle: "command", Values: []string{"/bin/sh -c nginx"}},
+		{Title: "STATUS", ExprTitle: "status", Values: []string{"Running"}},
+	}
+	titles := []string{"NAME", "IMAGE", "COMMAND", "STATUS"}
+
+	tests := []struct {
+		name        string
+		tableWidth  int
+		maxWidths   map[string]int
+		dropOrder   []string
+		wantColumns []int
+		wantWidths  []int
+		wantHidden  []string
+	}{
+		{
+			name:        "no limit",
+			wantColumns: []int{0, 1, 2, 3},
+			wantWidths:  []int{5, 30, 16, 7},
+		},
+		{
+			name:        "max width without a limit",
+			maxWidths:   map[string]int{"image": 10, "": 12},
+			wantColumns: []int{0, 1, 2, 3},
+			wantWidths:  []int{5, 10, 12, 7},
+		},
+		{
+			name:        "shrinks the widest columns",
+			tableWidth:  50,
+			wantColumns: []int{0, 1, 2, 3},
+			wantWidths:  []int{5, 14, 15, 7},
+		},
+		{
+			name:        "drops from the last column",
+			tableWidth:  30,
+			wantColumns: []int{0, 1, 2},
+			wantWidths:  []int{5, 9, 10, 7},
+			wantHidden:  []string{"STATUS"},
+		},
+		{
+			name:        "drops by priority",
+			tableWidth:  30,
+			dropOrder:   []string{"image", "missing"},
+			wantColumns: []int{0, 2, 3},
+			wantWidths:  []int{5, 30, 12, 7},
+			wantHidden:  []string{"IMAGE"},
+		},
+		{
+			name:        "drops by priority before the last column",
+			tableWidth:  20,
+			dropOrder:   []string{"IMAGE", "name"},
+			wantColumns: []int{2, 3},
+			wantWidths:  []int{5, 30, 10, 7},
+			wantHidden:  []string{"NAME", "IMAGE"},
+		},
+		{
+			name:        "cuts a single column to the limit",
+			tableWidth:  6,
+			dropOrder:   []string{"name"},
+			wantColumns: []int{1},
+			wantWidths:  []int{5, 6, 16, 7},
+			wantHidden:  []string{"NAME", "COMMAND", "STATUS"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSettings()
+			opts.tableWidth = tt.tableWidth
+			opts.columnMaxWidths = tt.maxWidths
+			opts.dropOrder = tt.dropOrder
+
+			got := fitTable(output, titles, opts)
+
+			if !reflect.DeepEqual(got.columns, tt.wantColumns) {
+				t.Errorf("columns = %v, want %v", got.columns, tt.wantColumns)
+			}
+
+			if !reflect.DeepEqual(got.widths, tt.wantWidths) {
+				t.Errorf("widths = %v, want %v", got.widths, tt.wantWidths)
+			}
+
+			if hidden := got.hidden(output); !reflect.DeepEqual(hidden, tt.wantHidden) {
+				t.Errorf("hidden = %v, want %v", hidden, tt.wantHidden)
+			}
+
+			if got.fitted != (tt.tableWidth > 0) {
+				t.Errorf("fitted = %v, want %v", got.fitted, tt.tableWidth > 0)
+			}
+		})
+	}
+}
+
+func Test_printHidden(t *testing.T) {
+	var b bytes.Buffer
+
+	printHidden(&b, nil)
+	if b.Len() != 0 {
+		t.Errorf("expected no notice when no columns are hidden, got %q", b.String())
+	}
+
+	printHidden(&b, []string{"IMAGE", "COMMAND"})
+	if want := "columns hidden to fit the width: IMAGE, COMMAND (use --width -1 to show them)\n"; b.String() != want {
+		t.Errorf("notice = %q, want %q", b.String(), want)
+	}
+}
+
+func Test_layoutCell(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		decorated string
+		width     int
+		wrap      bool
+		want      []cellLine
+	}{
+		{
+			name:  "fits",
+			text:  "web-1",
+			width: 8,
+			want:  []cellLine{{text: "web-1", width: 5}},
+		},
+		{
+			name:  "cut",
+			text:  "registry.example.com",
+			width: 10,
+			want:  []cellLine{{text: "registry.…", width: 10}},
+		},
+		{
+			name:      "cut keeps colors",
+			text:      "Running now",
+			decorated: "\033[32mRunning now\033[0m",
+			width:     8,
+			want:      []cellLine{{text: "\033[32mRunning\033[0m…", width: 8}},
+		},
+		{
+			name:  "wrap at spaces",
+			text:  "/bin/sh -c exec nginx",
+			width: 10,
+			wrap:  true,
+			want: []cellLine{
+				{text: "/bin/sh -c", width: 10},
+				{text: "exec nginx", width: 10},
+			},
+		},
+		{
+			name:  "wrap long words",
+			text:  "registry.example.com",
+			width: 8,
+			wrap:  true,
+			want: []cellLine{
+				{text: "registry", width: 8},
+				{text: ".example", width: 8},
+				{text: ".com", width: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decorated := tt.decorated
+			if decorated == "" {
+				decorated = tt.text
+			}
+
+			got := layoutCell(tt.text, decorated, tt.width, tt.wrap)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("layoutCell(%q, %d, %v) = %q, want %q", tt.text, tt.width, tt.wrap, got, tt.want)
+			}
+		})
+	}
+}
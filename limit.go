@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// resolveLimits validates the flags limiting the rows to print and, when
+// sampling without a seed, picks one, so every refresh of --watch samples
+// the same rows.
+func resolveLimits(opts *settings) error {
+	counts := []struct {
+		flag  string
+		value int
+	}{
+		{"limit", opts.limit},
+		{"offset", opts.offset},
+		{"tail", opts.tail},
+		{"sample", opts.sample},
+	}
+
+	for _, c := range counts {
+		if c.value < 0 {
+			return fmt.Errorf("--%s must be zero or greater, got %d", c.flag, c.value)
+		}
+	}
+
+	if opts.tail > 0 && (opts.limit > 0 || opts.offset > 0) {
+		return fmt.Errorf("cannot use --tail with --limit or --offset")
+	}
+
+	if opts.sample > 0 && (opts.limit > 0 || opts.offset > 0 || opts.tail > 0) {
+		return fmt.Errorf("cannot use --sample with --limit, --offset or --tail")
+	}
+
+	if opts.interactive && limitsRows(*opts) {
+		return fmt.Errorf("cannot use --interactive with --limit, --offset, --tail or --sample")
+	}
+
+	if opts.changes && opts.watch == 0 && (opts.offset > 0 || opts.tail > 0 || opts.sample > 0) {
+		return fmt.Errorf("cannot use --offset, --tail or --sample with --changes on streamed input, only --limit")
+	}
+
+	if opts.sample > 0 && opts.seed == 0 {
+		opts.seed = int(time.Now().UnixNano())
+	}
+
+	return nil
+}
+
+// limitsRows reports whether any of the flags limiting the rows was given.
+func limitsRows(opts settings) bool {
+	return opts.limit > 0 || opts.offset > 0 || opts.tail > 0 || opts.sample > 0
+}
+
+// limitRows keeps the rows requested with --limit, --offset, --tail or
+// --sample. It must be called once the rows are filtered and sorted.
+func limitRows(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) []tabloid.Column {
+	switch {
+	case opts.sample > 0:
+		return tab.Sample(cols, opts.sample, int64(opts.seed))
+	case opts.tail > 0:
+		return tab.Tail(cols, opts.tail)
+	case opts.limit > 0 || opts.offset > 0:
+		return tab.Page(cols, opts.offset, opts.limit)
+	}
+
+	return cols
+}
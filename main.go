@@ -1,14 +1,40 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
 
+// Exit codes follow grep's convention: 0 when rows were matched, 1 when none
+// were, or when the result is not the one expected, and 2 for errors.
+// Cancelling an interactive session exits like an interrupted shell command
+// would.
+const (
+	exitCodeNoMatch   = 1
+	exitCodeError     = 2
+	exitCodeCancelled = 130
+)
+
+// exitError signals that tabloid must exit with a specific code without
+// being an actual failure, so no error message is printed.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
 func main() {
 	if err := rootCommand(os.Stdin).Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+
 		errfn("Error: %s", err)
-		os.Exit(1)
+		os.Exit(exitCodeError)
 	}
 }
 
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+// cellPadding is the amount of spaces between columns, the same one used by
+// kubectl and docker.
+const cellPadding = 3
+
+// headerRow is the row number given to a cellDecorator for column titles.
+const headerRow = -1
+
+// cellDecorator allows changing how a cell is printed, like adding terminal
+// escape sequences, without affecting the column alignment, which is always
+// calculated using the original text.
+type cellDecorator func(row, col int, text string) string
+
+// Output formats supported by --output.
+const (
+	outputTable  = "table"
+	outputNDJSON = "ndjson"
+	outputCSV    = "csv"
+)
+
+// outputFormats is the list of all the supported output formats.
+var outputFormats = []string{outputTable, outputNDJSON, outputCSV}
+
+// validateOutput checks that the given output format is supported.
+func validateOutput(format string) error {
+	for _, v := range outputFormats {
+		if v == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown output format %q, must be one of: %s", format, strings.Join(outputFormats, ", "))
+}
+
+// printOutput writes the columns using the output format in the settings.
+func printOutput(w io.Writer, output []tabloid.Column, opts settings) error {
+	if len(output) == 0 {
+		return fmt.Errorf("input had no columns to handle")
+	}
+
+	switch opts.output {
+	case outputNDJSON:
+		return printNDJSON(w, output)
+	case outputCSV:
+		return printCSV(w, output, opts)
+	case outputTable, "":
+		return printTable(w, output, opts)
+	}
+
+	return validateOutput(opts.output)
+}
+
+// printNDJSON writes every row as a JSON object in its own line, using the
+// normalized column titles as keys, in the same order as the columns.
+func printNDJSON(w io.Writer, output []tabloid.Column) error {
+	bw := bufio.NewWriter(w)
+
+	for i := 0; i < rowsIn(output); i++ {
+		var line bytes.Buffer
+		line.WriteByte('{')
+
+		for col, v := range output {
+			if col > 0 {
+				line.WriteByte(',')
+			}
+
+			key, err := json.Marshal(v.ExprTitle)
+			if err != nil {
+				return fmt.Errorf("unable to encode column title %q: %w", v.ExprTitle, err)
+			}
+
+			value, err := json.Marshal(v.Values[i])
+			if err != nil {
+				return fmt.Errorf("unable to encode value %q: %w", v.Values[i], err)
+			}
+
+			line.Write(key)
+			line.WriteByte(':')
+			line.Write(value)
+		}
+
+		line.WriteString("}\n")
+		bw.Write(line.Bytes())
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("unable to write output: %w", err)
+	}
+
+	return nil
+}
+
+// printCSV writes the columns as comma-separated values, with the column
+// titles in the first line unless they were disabled.
+func printCSV(w io.Writer, output []tabloid.Column, opts settings) error {
+	cw := csv.NewWriter(w)
+
+	if !opts.noTitles {
+		cw.Write(tableTitles(output, opts))
+	}
+
+	for i := 0; i < rowsIn(output); i++ {
+		record := make([]string, 0, len(output))
+		for _, v := range output {
+			record = append(record, v.Values[i])
+		}
+		cw.Write(record)
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("unable to write output: %w", err)
+	}
+
+	return nil
+}
+
+// printTable writes the columns as a table, using the same format as
+// kubectl and docker, and lists the columns dropped to fit it in stderr.
+func printTable(w io.Writer, output []tabloid.Column, opts settings) error {
+	hidden, err := writeTable(w, output, opts, chainDecorators(styledCells(output), boldTitles(opts)))
+	if err != nil {
+		return err
+	}
+
+	printHidden(os.Stderr, hidden)
+	return nil
+}
+
+// printHidden lists the columns dropped from a table to fit it in the
+// width available, if any, so they aren't left out without notice.
+func printHidden(w io.Writer, hidden []string) {
+	if len(hidden) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "columns hidden to fit the width: %s (use --width -1 to show them)\n", strings.Join(hidden, ", "))
+}
+
+// styledCells returns a decorator that prints the values with their terminal
+// styling, for the columns that have it.
+func styledCells(output []tabloid.Column) cellDecorator {
+	return func(row, col int, text string) string {
+		if row == headerRow || row >= len(output[col].Styled) {
+			return text
+		}
+
+		return output[col].Styled[row]
+	}
+}
+
+// chainDecorators returns a decorator that applies all the given ones, in
+// order, skipping the nil ones.
+func chainDecorators(decorators ...cellDecorator) cellDecorator {
+	return func(row, col int, text string) string {
+		for _, decorate := range decorators {
+			if decorate != nil {
+				text = decorate(row, col, text)
+			}
+		}
+
+		return text
+	}
+}
+
+// writeTable writes the columns as a table, aligning them the same way a Go
+// tabwriter would, or drawing borders around them, depending on the table
+// style, while passing every cell through the decorator, if any. Cells are
+// decorated before being cut or wrapped to fit their column. It returns the
+// titles of the columns dropped to fit the table, if any.
+func writeTable(w io.Writer, output []tabloid.Column, opts settings, decorate cellDecorator) ([]string, error) {
+	if len(output) == 0 {
+		return nil, fmt.Errorf("input had no columns to handle")
+	}
+
+	style := opts.tableStyle()
+	titles := tableTitles(output, opts)
+	fit := fitTable(output, titles, opts)
+
+	widths := make([]int, 0, len(fit.columns))
+	rightAligned := make([]bool, len(output))
+	for _, col := range fit.columns {
+		widths = append(widths, fit.widths[col])
+		rightAligned[col] = opts.alignNumbers && output[col].Numeric()
+	}
+
+	padding := strings.Repeat(" ", style.padding)
+
+	// The spaces after the last column are only kept in borderless tables
+	// using the default separator, like a tabwriter would do.
+	trailing := !style.boxed && opts.separator == "" && !fit.fitted
+
+	bw := bufio.NewWriter(w)
+
+	writeRow := func(row int, cells func(col int) string) {
+		lines := make([][]cellLine, len(fit.columns))
+		height := 1
+
+		for i, col := range fit.columns {
+			text := cells(col)
+
+			decorated := text
+			if decorate != nil {
+				decorated = decorate(row, col, text)
+			}
+
+			lines[i] = layoutCell(text, decorated, fit.widths[col], opts.wrap)
+			if len(lines[i]) > height {
+				height = len(lines[i])
+			}
+		}
+
+		for line := 0; line < height; line++ {
+			if style.boxed {
+				bw.WriteString(style.vertical)
+			}
+
+			for i, col := range fit.columns {
+				var cell cellLine
+				if line < len(lines[i]) {
+					cell = lines[i][line]
+				}
+
+				space := strings.Repeat(" ", fit.widths[col]-cell.width)
+				last := i == len(fit.columns)-1
+
+				if style.boxed {
+					bw.WriteString(padding)
+				}
+
+				if rightAligned[col] {
+					bw.WriteString(space + cell.text)
+				} else {
+					bw.WriteString(cell.text)
+					if !last || style.boxed || trailing {
+						bw.WriteString(space)
+					}
+				}
+
+				switch {
+				case style.boxed:
+					bw.WriteString(padding)
+					if !last {
+						bw.WriteString(style.separator)
+					}
+				case !last || trailing:
+					bw.WriteString(style.gap())
+				}
+			}
+
+			if style.boxed {
+				bw.WriteString(style.vertical)
+			}
+			bw.WriteString("\n")
+		}
+	}
+
+	writeTitles := func() {
+		writeRow(headerRow, func(col int) string { return titles[col] })
+		bw.WriteString(style.border(style.middle, widths))
+	}
+
+	bw.WriteString(style.border(style.top, widths))
+
+	if !opts.noTitles {
+		writeTitles()
+	}
+
+	for i := 0; i < len(output[0].Values); i++ {
+		if !opts.noTitles && opts.repeatTitles > 0 && i > 0 && i%opts.repeatTitles == 0 {
+			bw.WriteString(style.border(style.middle, widths))
+			writeTitles()
+		}
+
+		writeRow(i, func(col int) string { return output[col].Values[i] })
+	}
+
+	bw.WriteString(style.border(style.bottom, widths))
+
+	if err := bw.Flush(); err != nil {
+		return nil, fmt.Errorf("unable to flush table contents to screen: %w", err)
+	}
+
+	return fit.hidden(output), nil
+}
+
+// tableTitles returns the titles to display for each column.
+func tableTitles(output []tabloid.Column, opts settings) []string {
+	titles := make([]string, 0, len(output))
+	for _, v := range output {
+		if opts.titlesNormalized {
+			titles = append(titles, v.ExprTitle)
+			continue
+		}
+
+		titles = append(titles, v.Title)
+	}
+
+	return titles
+}
+
+// columnWidths returns the width of each column, which is the width of its
+// longest value, including its title unless titles are disabled.
+func columnWidths(output []tabloid.Column, titles []string, opts settings) []int {
+	widths := make([]int, len(output))
+
+	for col, v := range output {
+		if !opts.noTitles {
+			widths[col] = utf8.RuneCountInString(titles[col])
+		}
+
+		for _, value := range v.Values {
+			if n := utf8.RuneCountInString(value); n > widths[col] {
+				widths[col] = n
+			}
+		}
+	}
+
+	return widths
+}
+
+// truncateANSI cuts a string to the given amount of visible characters,
+// keeping any terminal escape sequences in it intact.
+func truncateANSI(s string, width int) string {
+	var (
+		b       strings.Builder
+		visible int
+		cut     bool
+	)
+
+	for i := 0; i < len(s); {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			end := i + 2
+			for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+				end++
+			}
+			if end < len(s) {
+				end++
+			}
+
+			b.WriteString(s[i:end])
+			i = end
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if visible >= width {
+			cut = true
+			i += size
+			continue
+		}
+
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+
+	if cut && strings.Contains(s, "\033[") {
+		b.WriteString(tabloid.ANSIReset)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+	"github.com/spf13/cobra"
+)
+
+var presetsExamples = []string{
+	`tabloid presets`,
+	`kubectl get pods -A | tabloid @unhealthy`,
+	`kubectl get pods -A | tabloid @unhealthy --output ndjson`,
+}
+
+// configEnvVar is the environment variable that, when set, points to the
+// config file to use instead of the default one.
+const configEnvVar = "TABLOID_CONFIG"
+
+// presetPrefix marks an argument as a reference to a preset.
+const presetPrefix = "@"
+
+// config is the contents of the config file.
+type config struct {
+	Presets map[string]preset `json:"presets"`
+}
+
+// preset is a named set of flags. Every field maps to the flag with the
+// same name, and is only used when that flag wasn't given.
+type preset struct {
+	Description      string   `json:"description,omitempty"`
+	Expr             string   `json:"expr,omitempty"`
+	Columns          []string `json:"column,omitempty"`
+	ExcludeColumns   []string `json:"exclude-column,omitempty"`
+	Sort             string   `json:"sort,omitempty"`
+	SortDesc         bool     `json:"sort-desc,omitempty"`
+	Limit            int      `json:"limit,omitempty"`
+	Offset           int      `json:"offset,omitempty"`
+	Tail             int      `json:"tail,omitempty"`
+	Sample           int      `json:"sample,omitempty"`
+	Seed             int      `json:"seed,omitempty"`
+	Distinct         bool     `json:"distinct,omitempty"`
+	DistinctBy       []string `json:"distinct-by,omitempty"`
+	DistinctKeep     string   `json:"distinct-keep,omitempty"`
+	DistinctCount    bool     `json:"distinct-count,omitempty"`
+	Output           string   `json:"output,omitempty"`
+	NoTitles         bool     `json:"no-titles,omitempty"`
+	TitlesNormalized bool     `json:"titles-normalized,omitempty"`
+	Extracts         []string `json:"extract,omitempty"`
+	ExtractMissing   string   `json:"extract-missing,omitempty"`
+	AddColumns       []string `json:"add-column,omitempty"`
+	Renames          []string `json:"rename,omitempty"`
+	Profile          string   `json:"profile,omitempty"`
+	InputFormat      string   `json:"input-format,omitempty"`
+	ANSI             string   `json:"ansi,omitempty"`
+	Colors           []string `json:"color,omitempty"`
+	HighlightMatches bool     `json:"highlight-matches,omitempty"`
+	Width            int      `json:"width,omitempty"`
+	MaxWidths        []string `json:"max-width,omitempty"`
+	Wrap             bool     `json:"wrap,omitempty"`
+	DropOrder        []string `json:"drop-order,omitempty"`
+	Style            string   `json:"style,omitempty"`
+	Padding          *int     `json:"padding,omitempty"`
+	Separator        string   `json:"separator,omitempty"`
+	AlignNumbers     bool     `json:"align-numbers,omitempty"`
+	RepeatTitles     int      `json:"repeat-titles,omitempty"`
+	Delimiter        string   `json:"delimiter,omitempty"`
+	TabWidth         int      `json:"tab-width,omitempty"`
+}
+
+// configPath returns the location of the config file, either from the
+// environment or inside the user's config directory.
+func configPath() (string, error) {
+	if path := os.Getenv(configEnvVar); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find a config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "tabloid", "config.json"), nil
+}
+
+// loadConfig reads the config file. A missing file is not an error, and
+// results in an empty config.
+func loadConfig() (*config, string, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &config{}, path, nil
+	}
+
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to open config file: %w", err)
+	}
+	defer f.Close()
+
+	var cfg config
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, "", fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+
+	return &cfg, path, nil
+}
+
+// applyPreset finds the preset with the given name, without its prefix, and
+// uses its values for every flag that wasn't changed in the command line.
+func applyPreset(name string, changed func(flag string) bool, opts *settings) error {
+	cfg, path, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	p, found := cfg.Presets[name]
+	if !found {
+		return fmt.Errorf("preset %q not found in config file %s", name, path)
+	}
+
+	setString := func(flag string, dst *string, value string) {
+		if value != "" && !changed(flag) {
+			*dst = value
+		}
+	}
+
+	setSlice := func(flag string, dst *[]string, value []string) {
+		if len(value) > 0 && !changed(flag) {
+			*dst = value
+		}
+	}
+
+	setBool := func(flag string, dst *bool, value bool) {
+		if value && !changed(flag) {
+			*dst = value
+		}
+	}
+
+	setInt := func(flag string, dst *int, value int) {
+		if value != 0 && !changed(flag) {
+			*dst = value
+		}
+	}
+
+	setString("expr", &opts.expr, p.Expr)
+	setSlice("column", &opts.columns, p.Columns)
+	setSlice("exclude-column", &opts.excludeColumns, p.ExcludeColumns)
+	setString("sort", &opts.sort, p.Sort)
+	setBool("sort-desc", &opts.sortDesc, p.SortDesc)
+	setInt("limit", &opts.limit, p.Limit)
+	setInt("offset", &opts.offset, p.Offset)
+	setInt("tail", &opts.tail, p.Tail)
+	setInt("sample", &opts.sample, p.Sample)
+	setInt("seed", &opts.seed, p.Seed)
+	setBool("distinct", &opts.distinct, p.Distinct)
+	setSlice("distinct-by", &opts.distinctBy, p.DistinctBy)
+	setString("distinct-keep", &opts.distinctKeep, p.DistinctKeep)
+	setBool("distinct-count", &opts.distinctCount, p.DistinctCount)
+	setString("output", &opts.output, p.Output)
+	setBool("no-titles", &opts.noTitles, p.NoTitles)
+	setBool("titles-normalized", &opts.titlesNormalized, p.TitlesNormalized)
+	setSlice("extract", &opts.extracts, p.Extracts)
+	setString("extract-missing", &opts.extractMissing, p.ExtractMissing)
+	setSlice("add-column", &opts.addColumns, p.AddColumns)
+	setSlice("rename", &opts.renames, p.Renames)
+	setString("profile", &opts.profile, p.Profile)
+	setString("input-format", &opts.inputFormat, p.InputFormat)
+	setString("ansi", &opts.ansi, p.ANSI)
+	setSlice("color", &opts.colors, p.Colors)
+	setBool("highlight-matches", &opts.highlightMatches, p.HighlightMatches)
+	setInt("width", &opts.width, p.Width)
+	setSlice("max-width", &opts.maxWidths, p.MaxWidths)
+	setBool("wrap", &opts.wrap, p.Wrap)
+	setSlice("drop-order", &opts.dropOrder, p.DropOrder)
+	setString("style", &opts.style, p.Style)
+	if p.Padding != nil && !changed("padding") {
+		opts.padding = *p.Padding
+	}
+	setString("separator", &opts.separator, p.Separator)
+	setBool("align-numbers", &opts.alignNumbers, p.AlignNumbers)
+	setInt("repeat-titles", &opts.repeatTitles, p.RepeatTitles)
+	setString("delimiter", &opts.delimiter, p.Delimiter)
+	setInt("tab-width", &opts.tabWidth, p.TabWidth)
+
+	return nil
+}
+
+// presetReference splits a preset reference, like "@unhealthy", from the
+// rest of the arguments, if the first argument is one.
+func presetReference(args []string) (string, []string) {
+	if len(args) == 0 || !strings.HasPrefix(args[0], presetPrefix) {
+		return "", args
+	}
+
+	return strings.TrimPrefix(args[0], presetPrefix), args[1:]
+}
+
+// flags returns the command line flags equivalent to the preset.
+func (p preset) flags() []string {
+	var args []string
+
+	add := func(flag, value string) {
+		if value != "" {
+			args = append(args, "--"+flag, value)
+		}
+	}
+
+	addAll := func(flag string, values []string) {
+		for _, v := range values {
+			add(flag, v)
+		}
+	}
+
+	addBool := func(flag string, value bool) {
+		if value {
+			args = append(args, "--"+flag)
+		}
+	}
+
+	addInt := func(flag string, value int) {
+		if value != 0 {
+			add(flag, strconv.Itoa(value))
+		}
+	}
+
+	add("expr", p.Expr)
+	add("column", strings.Join(p.Columns, ","))
+	add("exclude-column", strings.Join(p.ExcludeColumns, ","))
+	add("sort", p.Sort)
+	addBool("sort-desc", p.SortDesc)
+	addInt("limit", p.Limit)
+	addInt("offset", p.Offset)
+	addInt("tail", p.Tail)
+	addInt("sample", p.Sample)
+	addInt("seed", p.Seed)
+	addBool("distinct", p.Distinct)
+	add("distinct-by", strings.Join(p.DistinctBy, ","))
+	add("distinct-keep", p.DistinctKeep)
+	addBool("distinct-count", p.DistinctCount)
+	add("output", p.Output)
+	addBool("no-titles", p.NoTitles)
+	addBool("titles-normalized", p.TitlesNormalized)
+	addAll("extract", p.Extracts)
+	add("extract-missing", p.ExtractMissing)
+	addAll("add-column", p.AddColumns)
+	addAll("rename", p.Renames)
+	add("profile", p.Profile)
+	add("input-format", p.InputFormat)
+	add("ansi", p.ANSI)
+	addAll("color", p.Colors)
+	addBool("highlight-matches", p.HighlightMatches)
+	addInt("width", p.Width)
+	add("max-width", strings.Join(p.MaxWidths, ","))
+	addBool("wrap", p.Wrap)
+	add("drop-order", strings.Join(p.DropOrder, ","))
+	add("style", p.Style)
+	if p.Padding != nil {
+		add("padding", strconv.Itoa(*p.Padding))
+	}
+	add("separator", p.Separator)
+	addBool("align-numbers", p.AlignNumbers)
+	addInt("repeat-titles", p.RepeatTitles)
+	add("delimiter", p.Delimiter)
+	addInt("tab-width", p.TabWidth)
+
+	return args
+}
+
+func presetsCommand(opts *settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "presets",
+		Short: "List the presets defined in the config file",
+		Long: `List the presets defined in the config file, which can be used as
+"tabloid @name". Flags given in the command line override the preset values.
+
+The config file is read from $` + configEnvVar + ` if set, or from
+tabloid/config.json inside the user's config directory otherwise.`,
+		Example: sliceToTabulated(presetsExamples),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			if len(cfg.Presets) == 0 {
+				fmt.Fprintf(os.Stderr, "no presets defined in config file %s\n", path)
+				return nil
+			}
+
+			return printOutput(os.Stdout, presetColumns(cfg.Presets), *opts)
+		},
+	}
+}
+
+// presetColumns converts the presets into columns, sorted by name, so they
+// can be printed with any output format.
+func presetColumns(presets map[string]preset) []tabloid.Column {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cols := []tabloid.Column{
+		{VisualPosition: 1, Title: "NAME", ExprTitle: "name"},
+		{VisualPosition: 2, Title: "DESCRIPTION", ExprTitle: "description"},
+		{VisualPosition: 3, Title: "FLAGS", ExprTitle: "flags"},
+	}
+
+	for _, name := range names {
+		values := []string{presetPrefix + name, presets[name].Description, quoteArgs(presets[name].flags())}
+		for i := range cols {
+			cols[i].Values = append(cols[i].Values, values[i])
+		}
+	}
+
+	return cols
+}
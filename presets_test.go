@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeConfig writes a config file for the test and points tabloid to it.
+func writeConfig(t *testing.T, contents string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(configEnvVar, path)
+}
+
+func Test_applyPreset(t *testing.T) {
+	writeConfig(t, `{
+		"presets": {
+			"unhealthy": {
+				"expr": "status != \"Running\"",
+				"column": ["name", "status"],
+				"sort": "name",
+				"limit": 5,
+				"padding": 0,
+				"wrap": true
+			}
+		}
+	}`)
+
+	tests := []struct {
+		name    string
+		changed []string
+		opts    settings
+		want    settings
+	}{
+		{
+			name: "preset values are used",
+			opts: settings{padding: 3},
+			want: settings{
+				expr:    `status != "Running"`,
+				columns: []string{"name", "status"},
+				sort:    "name",
+				limit:   5,
+				wrap:    true,
+			},
+		},
+		{
+			name:    "flags override preset values",
+			changed: []string{"sort", "limit", "padding", "column"},
+			opts:    settings{sort: "age", limit: 1, padding: 3},
+			want: settings{
+				expr:    `status != "Running"`,
+				sort:    "age",
+				limit:   1,
+				padding: 3,
+				wrap:    true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := func(flag string) bool {
+				for _, c := range tt.changed {
+					if c == flag {
+						return true
+					}
+				}
+				return false
+			}
+
+			opts := tt.opts
+			if err := applyPreset("unhealthy", changed, &opts); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(opts, tt.want) {
+				t.Errorf("settings = %+v, want %+v", opts, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applyPreset_errors(t *testing.T) {
+	never := func(string) bool { return false }
+
+	writeConfig(t, `{"presets": {"pods": {"limit": 1}}}`)
+	if err := applyPreset("nodes", never, &settings{}); err == nil {
+		t.Error("expected an error for a preset that doesn't exist")
+	}
+
+	writeConfig(t, `{"presets": {"pods": {"limits": 1}}}`)
+	if err := applyPreset("pods", never, &settings{}); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
+
+func Test_preset_flags(t *testing.T) {
+	padding := 0
+	p := preset{
+		Description: "not a flag",
+		Expr:        `status != "Running"`,
+		Columns:     []string{"name", "status"},
+		SortDesc:    true,
+		Limit:       5,
+		Colors:      []string{"status=red", "name=bold"},
+		Padding:     &padding,
+	}
+
+	want := []string{
+		"--expr", `status != "Running"`,
+		"--column", "name,status",
+		"--sort-desc",
+		"--limit", "5",
+		"--color", "status=red",
+		"--color", "name=bold",
+		"--padding", "0",
+	}
+
+	if got := p.flags(); !reflect.DeepEqual(got, want) {
+		t.Errorf("flags() = %q, want %q", got, want)
+	}
+}
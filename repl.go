@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+	"github.com/spf13/cobra"
+)
+
+var replExamples = []string{
+	`kubectl get pods -A | tabloid repl`,
+}
+
+const replHelp = `Commands:
+  where EXPR             filter the rows using an expression, or clear the filter if empty
+  select COLUMNS         display only the given columns, or all of them if empty
+  sort COLUMN [desc]     sort the rows by a column, or remove the sorting if empty
+  show                   print the current result
+  titles                 print the column titles
+  count                  print the amount of rows in the current result
+  export FORMAT FILE     write the current result to a file, using an output format
+  reset                  clear the filter, selection and sorting
+  history                list the previous commands
+  !N, !!                 run command number N from the history, or the last one
+  help                   print this help
+  quit, exit             leave the REPL`
+
+func replCommand(r io.Reader, opts *settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "repl",
+		Short: "Load a table once and explore it with repeated commands",
+		Long: `Load a table once and explore it with repeated commands.
+
+The input is parsed a single time, and commands are then read from the
+terminal, so expensive commands don't need to be re-run for every filter.
+
+` + replHelp,
+		Example: sliceToTabulated(replExamples),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := applySnapshot(cmd.Flags().Changed, opts); err != nil {
+				return err
+			}
+
+			if err := resolveOutput(opts); err != nil {
+				return err
+			}
+
+			tab, cols, err := load(r, *opts)
+			if err != nil {
+				return err
+			}
+
+			tty, err := openTerminal()
+			if err != nil {
+				return fmt.Errorf("the REPL requires a terminal to read commands from: %w", err)
+			}
+			defer tty.Close()
+
+			return newREPL(tab, cols, *opts).run(tty, os.Stdout, os.Stderr)
+		},
+	}
+}
+
+// repl holds the state of a REPL session: the loaded table, along with the
+// filter, selection and sorting applied to it.
+type repl struct {
+	tab  *tabloid.Tabloid
+	cols []tabloid.Column
+	opts settings
+
+	expr     string
+	columns  []string
+	sortBy   string
+	sortDesc bool
+	history  []string
+}
+
+func newREPL(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) *repl {
+	return &repl{tab: tab, cols: cols, opts: opts}
+}
+
+// run reads commands, one per line, until the input ends or the session is
+// quit. Errors in commands are printed without ending the session.
+func (s *repl) run(r io.Reader, w, errw io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	fmt.Fprintf(errw, "loaded %d rows and %d columns, type \"help\" for the list of commands\n", rowsIn(s.cols), len(s.cols))
+
+	for {
+		fmt.Fprint(errw, "tabloid> ")
+
+		if !scanner.Scan() {
+			fmt.Fprintln(errw)
+			return scanner.Err()
+		}
+
+		input := strings.TrimSpace(scanner.Text())
+		line, err := s.recall(input)
+		if err != nil {
+			fmt.Fprintf(errw, "Error: %s\n", err)
+			continue
+		}
+
+		if line != input {
+			fmt.Fprintln(errw, line)
+		}
+
+		if line == "" {
+			continue
+		}
+
+		if line != "history" {
+			s.history = append(s.history, line)
+		}
+
+		if err := s.exec(line, w, errw); err != nil {
+			if errors.Is(err, errQuit) {
+				return nil
+			}
+
+			fmt.Fprintf(errw, "Error: %s\n", err)
+		}
+	}
+}
+
+// errQuit is returned when a command ends the session.
+var errQuit = errors.New("quit")
+
+// recall replaces history references, like "!2" or "!!", with the command
+// they point to.
+func (s *repl) recall(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+
+	pos := len(s.history)
+	if line != "!!" {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid history reference %q", line)
+		}
+		pos = n
+	}
+
+	if pos < 1 || pos > len(s.history) {
+		return "", fmt.Errorf("history entry %q not found", line)
+	}
+
+	return s.history[pos-1], nil
+}
+
+// exec runs a single command.
+func (s *repl) exec(line string, w, errw io.Writer) error {
+	command, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(command) {
+	case "where":
+		if _, err := s.tab.Filter(s.cols, arg); err != nil {
+			return err
+		}
+
+		s.expr = arg
+		return s.show(w)
+
+	case "select":
+		columns := strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' })
+		if _, err := s.tab.Select(s.cols, columns); err != nil {
+			return err
+		}
+
+		s.columns = columns
+		return s.show(w)
+
+	case "sort":
+		by, direction, _ := strings.Cut(arg, " ")
+		direction = strings.ToLower(strings.TrimSpace(direction))
+
+		if direction != "" && direction != "asc" && direction != "desc" {
+			return fmt.Errorf("unknown sort direction %q, must be asc or desc", direction)
+		}
+
+		if by != "" {
+			if _, err := s.tab.Sort(s.cols, by, false); err != nil {
+				return err
+			}
+		}
+
+		s.sortBy, s.sortDesc = by, direction == "desc"
+		return s.show(w)
+
+	case "show":
+		return s.show(w)
+
+	case "titles":
+		output, err := s.result()
+		if err != nil {
+			return err
+		}
+
+		for _, v := range output {
+			fmt.Fprintln(w, v.Title)
+		}
+		return nil
+
+	case "count":
+		output, err := s.result()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, rowsIn(output))
+		return nil
+
+	case "export":
+		return s.export(arg, errw)
+
+	case "reset":
+		s.expr, s.columns, s.sortBy, s.sortDesc = "", nil, "", false
+		return s.show(w)
+
+	case "history":
+		for pos, v := range s.history {
+			fmt.Fprintf(w, "%4d  %s\n", pos+1, v)
+		}
+		return nil
+
+	case "help":
+		fmt.Fprintln(w, replHelp)
+		return nil
+
+	case "quit", "exit":
+		return errQuit
+	}
+
+	return fmt.Errorf("unknown command %q, type \"help\" for the list of commands", command)
+}
+
+// result applies the filter, sorting and selection to the loaded table.
+func (s *repl) result() ([]tabloid.Column, error) {
+	output, err := s.tab.Filter(s.cols, s.expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.sortBy != "" {
+		output, err = s.tab.Sort(output, s.sortBy, s.sortDesc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.tab.Select(output, s.columns)
+}
+
+// show prints the current result using the output format in the settings.
+func (s *repl) show(w io.Writer) error {
+	output, err := s.result()
+	if err != nil {
+		return err
+	}
+
+	return printOutput(w, output, s.opts)
+}
+
+// export writes the current result to a file, in the given output format.
+func (s *repl) export(arg string, errw io.Writer) error {
+	format, filename, _ := strings.Cut(arg, " ")
+	filename = strings.TrimSpace(filename)
+
+	if format == "" || filename == "" {
+		return fmt.Errorf("export requires a format and a file name, like: export csv pods.csv")
+	}
+
+	if err := validateOutput(format); err != nil {
+		return err
+	}
+
+	output, err := s.result()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("unable to create export file: %w", err)
+	}
+	defer f.Close()
+
+	opts := s.opts
+	opts.output = format
+	if err := printOutput(f, output, opts); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("unable to write export file: %w", err)
+	}
+
+	fmt.Fprintf(errw, "exported %d rows to %s\n", rowsIn(output), filename)
+	return nil
+}
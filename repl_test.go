@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_repl_run(t *testing.T) {
+	export := filepath.Join(t.TempDir(), "pods.csv")
+
+	tests := []struct {
+		name       string
+		commands   []string
+		wantOutput string
+		wantErrors []string
+	}{
+		{
+			name:       "where",
+			commands:   []string{`where status == "Error"`, "select name", "show"},
+			wantOutput: "NAMESPACE,NAME,READY,STATUS,RESTARTS,AGE\ndefault,web-2,0/1,Error,3,1d\nNAME\nweb-2\nNAME\nweb-2\n",
+		},
+		{
+			name:       "select and count",
+			commands:   []string{"select name, status", "titles", "count"},
+			wantOutput: "NAME,STATUS\nweb-1,Running\nweb-2,Error\nNAME\nSTATUS\n2\n",
+		},
+		{
+			name:       "sort",
+			commands:   []string{"select name", "sort restarts desc", "sort"},
+			wantOutput: "NAME\nweb-1\nweb-2\nNAME\nweb-2\nweb-1\nNAME\nweb-1\nweb-2\n",
+		},
+		{
+			name:       "history and recall",
+			commands:   []string{"count", "select name", "history", "!1", "!!"},
+			wantOutput: "2\nNAME\nweb-1\nweb-2\n   1  count\n   2  select name\n2\n2\n",
+		},
+		{
+			name:       "bad expressions keep the previous state",
+			commands:   []string{`where status == "Error"`, "where status ==", "count", "where nope == 1", "count"},
+			wantOutput: "NAMESPACE,NAME,READY,STATUS,RESTARTS,AGE\ndefault,web-2,0/1,Error,3,1d\n1\n1\n",
+			wantErrors: []string{"Error: unable to process expression", "Error: "},
+		},
+		{
+			name:       "unknown commands and history entries",
+			commands:   []string{"list", "!5", "sort name sideways", "count"},
+			wantOutput: "2\n",
+			wantErrors: []string{`unknown command "list"`, `history entry "!5" not found`, `unknown sort direction "sideways"`},
+		},
+		{
+			name:       "quit ends the session",
+			commands:   []string{"count", "quit", "count"},
+			wantOutput: "2\n",
+		},
+		{
+			name:       "export",
+			commands:   []string{"select name", "export csv " + export, "export yaml " + export},
+			wantOutput: "NAME\nweb-1\nweb-2\n",
+			wantErrors: []string{"exported 2 rows to " + export, "Error: "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSettings()
+			opts.output = outputCSV
+
+			tab, cols, err := load(strings.NewReader(podsInput), opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var out, errOut bytes.Buffer
+			input := strings.NewReader(strings.Join(tt.commands, "\n") + "\n")
+			if err := newREPL(tab, cols, opts).run(input, &out, &errOut); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := out.String(); got != tt.wantOutput {
+				t.Errorf("output = %q, want %q", got, tt.wantOutput)
+			}
+
+			for _, want := range tt.wantErrors {
+				if !strings.Contains(errOut.String(), want) {
+					t.Errorf("errors = %q, want them to contain %q", errOut.String(), want)
+				}
+			}
+		})
+	}
+
+	b, err := os.ReadFile(export)
+	if err != nil {
+		t.Fatalf("unable to read export: %s", err)
+	}
+
+	if got, want := string(b), "NAME\nweb-1\nweb-2\n"; got != want {
+		t.Errorf("export = %q, want %q", got, want)
+	}
+}
+
+func TestRootCommand_replValidatesFlags(t *testing.T) {
+	err := execute(podsInput, "repl", "-o", "bogus")
+	if err == nil || !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("error = %v, want an error about the output format", err)
+	}
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
-	"text/tabwriter"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/patrickdappollonio/tabloid/tabloid"
 	"github.com/spf13/cobra"
@@ -24,58 +28,435 @@ var examples = []string{
 	`kubectl api-resources | tabloid --expr 'apiversion =~ "networking"'`,
 	`kubectl api-resources | tabloid --expr 'shortnames == "sa"' --column name,shortnames`,
 	`kubectl get pods --all-namespaces | tabloid --expr 'name =~ "^frontend" || name =~ "redis$"'`,
+	`kubectl get pods | tabloid --expr 'status == "Completed"' --exec 'kubectl delete pod {{.name}}' --dry-run`,
+	`tabloid --watch 5s --expr 'isnotready(ready)' -- kubectl get pods --all-namespaces`,
+	`kubectl api-resources | tabloid -i`,
+	`kubectl get pods -A | tabloid --save pods && tabloid --from pods --expr 'restarts > 5'`,
+	`kubectl get pods -A | tabloid @unhealthy`,
 }
 
 type settings struct {
 	expr             string
 	columns          []string
+	excludeColumns   []string
+	sort             string
+	sortDesc         bool
+	limit            int
+	offset           int
+	tail             int
+	sample           int
+	seed             int
+	distinct         bool
+	distinctBy       []string
+	distinctKeep     string
+	distinctCount    bool
 	debug            bool
 	noTitles         bool
 	titlesOnly       bool
 	titlesNormalized bool
+	explain          bool
+	interactive      bool
+	watch            time.Duration
+	noHighlight      bool
+	output           string
+	changes          bool
+	keys             []string
+	exec             string
+	shell            bool
+	dryRun           bool
+	parallel         int
+	confirm          bool
+	failIfEmpty      bool
+	failIfAny        bool
+	addColumns       []string
+	extracts         []string
+	renames          []string
+	extractMissing   string
+	save             string
+	from             string
+	profile          string
+	delimiter        string
+	tabWidth         int
+	inputFormat      string
+	ansi             string
+	colors           []string
+	highlightMatches bool
+	width            int
+	maxWidths        []string
+	wrap             bool
+	dropOrder        []string
+	style            string
+	padding          int
+	separator        string
+	alignNumbers     bool
+	repeatTitles     int
+
+	// colorRules and useColor are resolved from the --color values.
+	colorRules []tabloid.ColorRule
+	useColor   bool
+
+	// columnMaxWidths and tableWidth are resolved from --max-width and
+	// --width, and tableWidth is 0 when there's no limit.
+	columnMaxWidths map[string]int
+	tableWidth      int
+
+	// table is resolved from --style, --padding and --separator.
+	table tableStyle
+
+	// keep is resolved from --distinct-keep.
+	keep tabloid.DistinctKeep
+
+	// source is the command given after "--", if any, used as the input.
+	source []string
 }
 
 func rootCommand(r io.Reader) *cobra.Command {
 	var opts settings
 
 	cmd := &cobra.Command{
-		Use:           "tabloid",
+		Use:           "tabloid [@preset] [flags] [-- command [args...]]",
 		Short:         helpShort,
 		Long:          helpLong,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Version:       version,
 		Example:       sliceToTabulated(examples),
+		Args:          cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			name, args := presetReference(args)
+			if name != "" {
+				if err := applyPreset(name, cmd.Flags().Changed, &opts); err != nil {
+					return err
+				}
+			}
+
+			if err := applySnapshot(cmd.Flags().Changed, &opts); err != nil {
+				return err
+			}
+
+			if err := resolveOutput(&opts); err != nil {
+				return err
+			}
+
+			if err := resolveLimits(&opts); err != nil {
+				return err
+			}
+
+			if err := resolveDistinct(&opts); err != nil {
+				return err
+			}
+
+			if opts.changes && len(opts.keys) == 0 {
+				return fmt.Errorf("--changes requires at least one --key column to identify rows")
+			}
+
+			if opts.interactive && (opts.watch > 0 || opts.changes) {
+				return fmt.Errorf("cannot use --interactive with --watch or --changes")
+			}
+
+			if opts.interactive && runtime.GOOS == "windows" {
+				return fmt.Errorf("--interactive is not supported on windows")
+			}
+
+			if opts.from != "" && len(args) > 0 {
+				return fmt.Errorf("cannot use --from with a command to run")
+			}
+
+			if opts.changes && opts.watch == 0 && (opts.save != "" || opts.from != "") {
+				return fmt.Errorf("cannot use --save or --from with --changes on streamed input")
+			}
+
+			if opts.changes && opts.watch == 0 && opts.inputFormat == string(tabloid.InputJSON) {
+				return fmt.Errorf("cannot use --changes on streamed input with --input-format %s, use --watch instead", tabloid.InputJSON)
+			}
+
+			opts.source = args
+
+			if opts.watch > 0 {
+				if len(args) == 0 {
+					return fmt.Errorf("--watch requires a command to run, given after \"--\"")
+				}
+
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+				defer stop()
+
+				if opts.changes {
+					return watchChanges(ctx, os.Stdout, os.Stderr, args, opts)
+				}
+
+				return watch(ctx, os.Stdout, os.Stderr, args, opts)
+			}
+
+			if len(args) > 0 {
+				stdout, stderr, err := runSource(context.Background(), args)
+				if err != nil {
+					return err
+				}
+
+				if stderr != "" {
+					fmt.Fprint(os.Stderr, stderr)
+				}
+
+				r = stdout
+			}
+
+			if opts.changes {
+				return streamChanges(r, os.Stdout, opts)
+			}
+
 			return run(r, os.Stdout, opts)
 		},
 	}
 
+	cmd.AddCommand(assertCommand(r, &opts))
+	cmd.AddCommand(replCommand(r, &opts))
+	cmd.AddCommand(snapshotsCommand(&opts))
+	cmd.AddCommand(presetsCommand(&opts))
+
 	cmd.Flags().StringVarP(&opts.expr, "expr", "e", "", "expression to filter the output")
-	cmd.Flags().StringSliceVarP(&opts.columns, "column", "c", []string{}, "columns to display")
-	cmd.Flags().BoolVar(&opts.debug, "debug", false, "enable debug mode")
+	cmd.Flags().StringSliceVarP(&opts.columns, "column", "c", []string{}, "columns to display, by title, position, range of positions or glob pattern; use * for all remaining columns")
+	cmd.Flags().StringSliceVar(&opts.excludeColumns, "exclude-column", []string{}, "columns to hide, by title, position, range of positions or glob pattern")
+	cmd.Flags().StringVar(&opts.sort, "sort", "", "sort the rows by a column, comparing values as numbers when both are numeric")
+	cmd.Flags().BoolVar(&opts.sortDesc, "sort-desc", false, "sort the rows in descending order when using --sort")
+	cmd.Flags().IntVar(&opts.limit, "limit", 0, "print at most this amount of rows, after filtering and sorting them; the whole input is still read, except with --changes on streamed input")
+	cmd.Flags().IntVar(&opts.offset, "offset", 0, "skip this amount of rows before printing the rest, after filtering and sorting them")
+	cmd.Flags().IntVar(&opts.tail, "tail", 0, "print only this amount of rows from the end, after filtering and sorting them")
+	cmd.Flags().IntVar(&opts.sample, "sample", 0, "print this amount of rows picked at random, after filtering them")
+	cmd.Flags().IntVar(&opts.seed, "seed", 0, "seed used to pick the rows with --sample, to get the same ones every time; 0 picks a different one every time")
+	cmd.Flags().BoolVar(&opts.distinct, "distinct", false, "remove the rows whose displayed values are the same as the ones of a previous row")
+	cmd.Flags().StringSliceVar(&opts.distinctBy, "distinct-by", []string{}, "remove the rows whose values in these columns are the same as the ones of another row, displayed or not")
+	cmd.Flags().StringVar(&opts.distinctKeep, "distinct-keep", string(tabloid.DistinctFirst), "which of the duplicated rows to keep with --distinct-by: "+string(tabloid.DistinctFirst)+" or "+string(tabloid.DistinctLast))
+	cmd.Flags().BoolVar(&opts.distinctCount, "distinct-count", false, "add a "+countTitle+" column with the amount of rows every distinct row stands for")
 	cmd.Flags().BoolVar(&opts.noTitles, "no-titles", false, "remove column titles from the output")
 	cmd.Flags().BoolVar(&opts.titlesOnly, "titles-only", false, "only display column titles")
 	cmd.Flags().BoolVar(&opts.titlesNormalized, "titles-normalized", false, "normalize column titles")
+	cmd.Flags().BoolVar(&opts.failIfEmpty, "fail-if-empty", false, "exit with code 1 if no rows are left after filtering, which is the default")
+	cmd.Flags().BoolVar(&opts.failIfAny, "fail-if-any", false, "exit with code 1 if any rows are left after filtering, and with 0 if none are")
+	cmd.Flags().DurationVar(&opts.watch, "watch", 0, "re-run the command given after \"--\" on this interval, refreshing the output")
+	cmd.Flags().BoolVar(&opts.noHighlight, "no-highlight", false, "do not highlight cells that changed between --watch refreshes")
+	cmd.Flags().BoolVar(&opts.changes, "changes", false, "only print rows that are new, changed or removed, from --watch refreshes or streamed input")
+	cmd.Flags().StringSliceVar(&opts.keys, "key", []string{}, "columns that identify a row when using --changes")
+	cmd.Flags().StringVar(&opts.exec, "exec", "", "run a command for every row, using placeholders like {{.name}} for the row values")
+	cmd.Flags().BoolVar(&opts.shell, "exec-shell", false, "run the --exec command through a shell instead of directly")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "print the --exec commands instead of running them")
+	cmd.Flags().IntVar(&opts.parallel, "parallel", 1, "amount of --exec commands to run at the same time")
+	cmd.Flags().BoolVar(&opts.confirm, "confirm", false, "ask for confirmation before running each --exec command")
+	cmd.Flags().BoolVar(&opts.explain, "explain", false, "print every row along with how the expression was evaluated for it")
+	cmd.Flags().StringArrayVar(&opts.colors, "color", []string{}, "when to colorize the table output, "+colorAuto+", "+colorAlways+" or "+colorNever+", or a rule to color the cells used by an expression, in the form of expression:style")
+	cmd.Flags().BoolVar(&opts.highlightMatches, "highlight-matches", false, "highlight the text matched by the regular expressions used with =~ in --expr, when colorizing the output")
+	cmd.Flags().IntVar(&opts.width, "width", widthAuto, "width to fit the table output in, cutting or wrapping cells and dropping the last columns if needed, which are listed in stderr; 0 uses the terminal width, -1 disables it")
+	cmd.Flags().StringSliceVar(&opts.maxWidths, "max-width", []string{}, "maximum width of a column, in the form of column=width, or of all columns, cutting or wrapping longer values")
+	cmd.Flags().BoolVar(&opts.wrap, "wrap", false, "wrap cells wider than their column onto more lines instead of cutting them")
+	cmd.Flags().StringSliceVar(&opts.dropOrder, "drop-order", []string{}, "columns to drop first, in order, when the table doesn't fit the width; the rest are dropped from the last one")
+	cmd.Flags().StringVar(&opts.style, "style", defaultStyle.name, "how to draw the table output: "+strings.Join(tableStyleNames(), ", "))
+	cmd.Flags().IntVar(&opts.padding, "padding", -1, "amount of spaces between columns, or around values in styles with borders; -1 uses the one of the style")
+	cmd.Flags().StringVar(&opts.separator, "separator", "", "characters drawn between columns, instead of the spaces or the border of the style")
+	cmd.Flags().BoolVar(&opts.alignNumbers, "align-numbers", false, "align the values of numeric columns to the right")
+	cmd.Flags().IntVar(&opts.repeatTitles, "repeat-titles", 0, "print the column titles again every this amount of rows")
+	cmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", false, "explore the table interactively, filtering it as the expression is typed, and print the chosen rows on enter")
+
+	cmd.PersistentFlags().StringSliceVar(&opts.renames, "rename", []string{}, "rename columns, in the form of column=title; renamed columns must be referenced by their new title")
+	cmd.PersistentFlags().StringArrayVar(&opts.extracts, "extract", []string{}, "add a column per named capture group of a regular expression matched against a column, in the form of column=regexp")
+	cmd.PersistentFlags().StringVar(&opts.extractMissing, "extract-missing", string(tabloid.ExtractEmpty), "what to do with rows not matching --extract: empty, drop or error")
+	cmd.PersistentFlags().StringArrayVar(&opts.addColumns, "add-column", []string{}, "add a column computed from an expression, in the form of name=expression")
+	cmd.PersistentFlags().StringVar(&opts.profile, "profile", profileAuto, "how to parse the output of well-known commands: "+profileAuto+" to detect it from the titles, "+profileNone+", or one of: "+strings.Join(tabloid.ProfileNames(), ", "))
+	cmd.PersistentFlags().StringVar(&opts.inputFormat, "input-format", string(tabloid.InputTable), "format of the input: "+strings.Join(tabloid.InputFormats(), ", "))
+	cmd.PersistentFlags().StringVar(&opts.ansi, "ansi", ansiStrip, "what to do with colors and other terminal escape sequences in the input: "+ansiStrip+" them, or "+ansiKeep+" them in table output")
+	cmd.PersistentFlags().StringVar(&opts.delimiter, "delimiter", delimiterAuto, "what separates the columns: "+delimiterAuto+" to detect titles separated by tabs, "+delimiterSpace+" for columns aligned with spaces, "+delimiterTab+", or any other string")
+	cmd.PersistentFlags().IntVar(&opts.tabWidth, "tab-width", 8, "distance between tab stops, used to expand tabs in columns aligned with both tabs and spaces")
+	cmd.PersistentFlags().StringVar(&opts.save, "save", "", "save the input as a snapshot with this name, to query it again later with --from")
+	cmd.PersistentFlags().StringVar(&opts.from, "from", "", "read the input from a snapshot saved with --save instead of the standard input")
+	cmd.PersistentFlags().StringVarP(&opts.output, "output", "o", outputTable, "output format: "+strings.Join(outputFormats, ", "))
+	cmd.PersistentFlags().BoolVar(&opts.debug, "debug", false, "enable debug mode")
 
 	return cmd
 }
 
-func run(r io.Reader, w io.Writer, opts settings) error {
-	var b bytes.Buffer
+// resolveOutput validates the flags deciding how the output is printed.
+func resolveOutput(opts *settings) error {
+	if err := validateOutput(opts.output); err != nil {
+		return err
+	}
 
-	if _, err := io.Copy(&b, r); err != nil {
+	if err := resolveColors(opts); err != nil {
 		return err
 	}
 
-	tab := tabloid.New(&b)
-	tab.EnableDebug(opts.debug)
+	if err := resolveStyle(opts); err != nil {
+		return err
+	}
+
+	return resolveLayout(opts)
+}
+
+// load reads and parses the input, either from the reader or from a saved
+// snapshot, then derives the requested columns. If requested, the input is
+// saved as a snapshot once it's known to be parseable.
+func load(r io.Reader, opts settings) (*tabloid.Tabloid, []tabloid.Column, error) {
+	var b bytes.Buffer
+
+	if opts.from != "" {
+		if err := readSnapshot(opts.from, &b); err != nil {
+			return nil, nil, err
+		}
+	} else if _, err := io.Copy(&b, r); err != nil {
+		return nil, nil, err
+	}
+
+	raw := b.Bytes()
+
+	tab, err := newTabloid(&b, opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	cols, err := tab.ParseColumns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.save != "" {
+		if err := saveSnapshot(opts.save, raw, cols, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	cols, err = derive(tab, cols, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tab, cols, nil
+}
+
+// Special values for --profile.
+const (
+	profileAuto = "auto"
+	profileNone = "none"
+)
+
+// Values for --ansi.
+const (
+	ansiStrip = "strip"
+	ansiKeep  = "keep"
+)
+
+// Special values for --delimiter.
+const (
+	delimiterAuto  = "auto"
+	delimiterSpace = "space"
+	delimiterTab   = "tab"
+)
+
+// newTabloid creates a parser for the input, using the input format,
+// profile and delimiter requested.
+func newTabloid(input *bytes.Buffer, opts settings) (*tabloid.Tabloid, error) {
+	tab := tabloid.New(input)
+	tab.EnableDebug(opts.debug)
+
+	format, err := tabloid.ParseInputFormat(opts.inputFormat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for --input-format: %w", err)
+	}
+	tab.SetInputFormat(format)
+
+	switch opts.ansi {
+	case ansiStrip, "":
+	case ansiKeep:
+		tab.KeepANSI(true)
+	default:
+		return nil, fmt.Errorf("invalid value for --ansi: must be %q or %q, got %q", ansiStrip, ansiKeep, opts.ansi)
+	}
+
+	switch opts.profile {
+	case profileAuto, "":
+		tab.DetectProfiles(true)
+	case profileNone:
+	default:
+		p, err := tabloid.LookupProfile(opts.profile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --profile: %w", err)
+		}
+		tab.UseProfile(p)
+	}
+
+	if opts.tabWidth < 1 {
+		return nil, fmt.Errorf("invalid value for --tab-width: must be at least 1, got %d", opts.tabWidth)
+	}
+	tab.SetTabWidth(opts.tabWidth)
+
+	switch opts.delimiter {
+	case delimiterAuto, "":
+		tab.DetectTabs(true)
+	case delimiterSpace:
+	case delimiterTab:
+		tab.SetDelimiter("\t")
+	default:
+		tab.SetDelimiter(opts.delimiter)
+	}
+
+	return tab, nil
+}
+
+// derive applies all the extractions, computed columns and renames
+// requested, in that order.
+func derive(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) ([]tabloid.Column, error) {
+	if len(opts.extracts) > 0 {
+		policy, err := tabloid.ParseExtractPolicy(opts.extractMissing)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --extract-missing: %w", err)
+		}
+
+		for _, v := range opts.extracts {
+			column, pattern, err := splitAssignment(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for --extract: %w", err)
+			}
+
+			cols, err = tab.Extract(cols, column, pattern, policy)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, v := range opts.addColumns {
+		name, expr, err := splitAssignment(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --add-column: %w", err)
+		}
+
+		cols, err = tab.AddColumn(cols, name, expr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, v := range opts.renames {
+		name, title, err := splitAssignment(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --rename: %w", err)
+		}
+
+		cols, err = tab.Rename(cols, name, title)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cols, nil
+}
+
+func run(r io.Reader, w io.Writer, opts settings) error {
+	tab, cols, err := load(r, opts)
 	if err != nil {
 		return err
 	}
 
+	if opts.failIfEmpty && opts.failIfAny {
+		return fmt.Errorf("cannot use --fail-if-empty with --fail-if-any")
+	}
+
 	if opts.titlesOnly {
 		if opts.expr != "" {
 			return fmt.Errorf("cannot use --expr with --titles-only")
@@ -85,7 +466,15 @@ func run(r io.Reader, w io.Writer, opts settings) error {
 			return fmt.Errorf("cannot use --column with --titles-only")
 		}
 
+		if len(opts.excludeColumns) > 0 {
+			return fmt.Errorf("cannot use --exclude-column with --titles-only")
+		}
+
 		for _, v := range cols {
+			if v.Hidden {
+				continue
+			}
+
 			if opts.titlesNormalized {
 				fmt.Fprintln(w, v.ExprTitle)
 				continue
@@ -96,44 +485,129 @@ func run(r io.Reader, w io.Writer, opts settings) error {
 		return nil
 	}
 
-	filtered, err := tab.Filter(cols, opts.expr)
+	if opts.explain {
+		if opts.expr == "" {
+			return fmt.Errorf("cannot use --explain without --expr")
+		}
+
+		explanations, err := tab.Explain(cols, opts.expr)
+		if err != nil {
+			return err
+		}
+
+		if p, found := tab.ActiveProfile(); found {
+			fmt.Fprintf(w, "parsed using the %s profile\n", p.Name)
+		}
+
+		printExplanations(w, cols, explanations)
+		return nil
+	}
+
+	if opts.interactive {
+		if opts.exec != "" {
+			return fmt.Errorf("cannot use --exec with --interactive")
+		}
+
+		return runInteractive(w, tab, cols, opts)
+	}
+
+	if opts.exec != "" {
+		// Commands can use any column, but --distinct only compares the
+		// ones that would be displayed.
+		filtered, output, err := project(tab, cols, opts)
+		if err != nil {
+			return err
+		}
+
+		distinct, err := distinctRows(tab, filtered, output, filtered, opts)
+		if err != nil {
+			return err
+		}
+
+		selected := limitRows(tab, distinct, opts)
+		if err := runExec(w, os.Stderr, selected, opts); err != nil {
+			return err
+		}
+
+		return matchResult(rowsIn(selected), opts)
+	}
+
+	output, err := process(tab, cols, opts)
 	if err != nil {
 		return err
 	}
 
-	output, err := tab.Select(filtered, opts.columns)
-	if err != nil {
+	if err := printOutput(w, output, opts); err != nil {
 		return err
 	}
 
-	if len(output) == 0 {
-		return fmt.Errorf("input had no columns to handle")
+	return matchResult(rowsIn(output), opts)
+}
+
+// matchResult returns the error to exit with, given the amount of rows left
+// after filtering. Like grep, it exits with exitCodeNoMatch when no rows
+// are left, unless --fail-if-any reverses it.
+func matchResult(rows int, opts settings) error {
+	if (rows == 0) != opts.failIfAny {
+		return &exitError{code: exitCodeNoMatch}
 	}
 
-	t := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	return nil
+}
 
-	if !opts.noTitles {
-		for _, v := range output {
-			if opts.titlesNormalized {
-				fmt.Fprintf(t, "%s\t", v.ExprTitle)
-				continue
-			}
+// process filters, sorts and colorizes the rows, then selects the columns
+// to display, removes the duplicated rows, if requested, and keeps the rows
+// requested with the limit flags.
+func process(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) ([]tabloid.Column, error) {
+	filtered, output, err := project(tab, cols, opts)
+	if err != nil {
+		return nil, err
+	}
 
-			fmt.Fprintf(t, "%s\t", v.Title)
-		}
-		fmt.Fprintln(t, "")
+	output, err = distinctRows(tab, output, output, filtered, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := 0; i < len(output[0].Values); i++ {
-		for _, v := range output {
-			fmt.Fprintf(t, "%s\t", v.Values[i])
-		}
-		fmt.Fprintln(t, "")
+	return limitRows(tab, output, opts), nil
+}
+
+// project filters, sorts and colorizes the rows, and returns them along
+// with the columns to display.
+func project(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) ([]tabloid.Column, []tabloid.Column, error) {
+	filtered, err := filter(tab, cols, opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := t.Flush(); err != nil {
-		return fmt.Errorf("unable to flush table contents to screen: %w", err)
+	filtered, err = colorize(tab, filtered, opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	output, err := tab.Select(filtered, opts.columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output, err = tab.Exclude(output, opts.excludeColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return filtered, output, nil
+}
+
+// filter keeps the rows matching the expression, sorted if requested.
+func filter(tab *tabloid.Tabloid, cols []tabloid.Column, opts settings) ([]tabloid.Column, error) {
+	filtered, err := tab.Filter(cols, opts.expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.sort == "" {
+		return filtered, nil
+	}
+
+	return tab.Sort(filtered, opts.sort, opts.sortDesc)
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const podsInput = `NAMESPACE   NAME    READY   STATUS    RESTARTS   AGE
+default     web-1   1/1     Running   0          8d
+default     web-2   0/1     Error     3          1d
+`
+
+// execute runs the root command with the given input and arguments.
+func execute(input string, args ...string) error {
+	cmd := rootCommand(strings.NewReader(input))
+	cmd.SetArgs(args)
+
+	return cmd.Execute()
+}
+
+func TestRootCommand_noColumnsLeft(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "table", args: []string{"--exclude-column", "*"}},
+		{name: "ndjson", args: []string{"-o", "ndjson", "--exclude-column", "*"}},
+		{name: "csv", args: []string{"-o", "csv", "--exclude-column", "1-6"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := execute(podsInput, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), "no columns") {
+				t.Errorf("error = %v, want an error about having no columns", err)
+			}
+		})
+	}
+}
+
+// testSettings returns the settings used when no flags are given.
+func testSettings() settings {
+	return settings{output: outputTable, tabWidth: 8}
+}
+
+func Test_run_exitCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		failIfAny bool
+		wantCode  int
+	}{
+		{name: "rows matched", expr: `status == "Running"`},
+		{name: "no rows matched", expr: `status == "Nope"`, wantCode: exitCodeNoMatch},
+		{name: "fail if any with rows", expr: `status == "Running"`, failIfAny: true, wantCode: exitCodeNoMatch},
+		{name: "fail if any without rows", expr: `status == "Nope"`, failIfAny: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSettings()
+			opts.expr = tt.expr
+			opts.failIfAny = tt.failIfAny
+
+			var out bytes.Buffer
+			err := run(strings.NewReader(podsInput), &out, opts)
+
+			code := 0
+			if exitErr, ok := err.(*exitError); ok {
+				code = exitErr.code
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+
+			if !strings.HasPrefix(out.String(), "NAMESPACE") {
+				t.Errorf("output = %q, want the table titles to be printed", out.String())
+			}
+		})
+	}
+}
+
+func Test_run_explainProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    string
+	}{
+		{name: "detected", want: "parsed using the kubectl profile\nrow 1: true\n"},
+		{name: "disabled", profile: profileNone, want: "row 1: true\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSettings()
+			opts.expr = `status == "Running"`
+			opts.explain = true
+			opts.profile = tt.profile
+
+			var out bytes.Buffer
+			if err := run(strings.NewReader(podsInput), &out, opts); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !strings.HasPrefix(out.String(), tt.want) {
+				t.Errorf("output = %q, want it to start with %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+	"github.com/spf13/cobra"
+)
+
+var snapshotsExamples = []string{
+	`kubectl get pods -A | tabloid --save pods`,
+	`tabloid --from pods --expr 'status != "Running"'`,
+	`tabloid snapshots`,
+	`tabloid snapshots prune --older-than 24h`,
+}
+
+// Snapshots are stored as a pair of files: the raw input, and a JSON file
+// with the details of when and how it was captured.
+const (
+	snapshotDataExt     = ".txt"
+	snapshotMetadataExt = ".json"
+)
+
+var reSnapshotName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// snapshot holds the metadata of a saved input.
+type snapshot struct {
+	Name    string        `json:"name"`
+	Created time.Time     `json:"created"`
+	Source  string        `json:"source,omitempty"`
+	Rows    int           `json:"rows"`
+	Columns []string      `json:"columns"`
+	Parse   snapshotParse `json:"parse"`
+}
+
+// snapshotParse holds the options a snapshot was parsed with, so it's
+// parsed the same way when read with --from. Every field maps to the flag
+// with the same name.
+type snapshotParse struct {
+	InputFormat string `json:"input-format,omitempty"`
+	Delimiter   string `json:"delimiter,omitempty"`
+	Profile     string `json:"profile,omitempty"`
+	ANSI        string `json:"ansi,omitempty"`
+	TabWidth    int    `json:"tab-width,omitempty"`
+}
+
+// snapshotDir returns the directory where snapshots are stored, inside the
+// user's cache directory.
+func snapshotDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find a cache directory for snapshots: %w", err)
+	}
+
+	return filepath.Join(dir, "tabloid", "snapshots"), nil
+}
+
+// validateSnapshotName checks that a snapshot name can be safely used as a
+// file name.
+func validateSnapshotName(name string) error {
+	if !reSnapshotName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: only letters, numbers, dots, dashes and underscores are allowed", name)
+	}
+
+	return nil
+}
+
+// saveSnapshot stores the raw input under the given name, along with the
+// titles and amount of rows it was parsed into and the options used to
+// parse it, replacing any previous snapshot with the same name.
+func saveSnapshot(name string, data []byte, cols []tabloid.Column, opts settings) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+
+	dir, err := snapshotDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create snapshot directory: %w", err)
+	}
+
+	meta := snapshot{
+		Name:    name,
+		Created: time.Now().UTC().Truncate(time.Second),
+		Source:  strings.Join(opts.source, " "),
+		Rows:    rowsIn(cols),
+		Columns: make([]string, 0, len(cols)),
+		Parse: snapshotParse{
+			InputFormat: opts.inputFormat,
+			Delimiter:   opts.delimiter,
+			Profile:     opts.profile,
+			ANSI:        opts.ansi,
+			TabWidth:    opts.tabWidth,
+		},
+	}
+
+	for _, c := range cols {
+		meta.Columns = append(meta.Columns, c.Title)
+	}
+
+	encoded, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode snapshot %q: %w", name, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name+snapshotDataExt), data, 0o644); err != nil {
+		return fmt.Errorf("unable to save snapshot %q: %w", name, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, name+snapshotMetadataExt), append(encoded, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to save snapshot %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// listSnapshots returns all saved snapshots, sorted by name.
+func listSnapshots() ([]snapshot, error) {
+	dir, err := snapshotDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+snapshotMetadataExt))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list snapshots: %w", err)
+	}
+
+	snapshots := make([]snapshot, 0, len(files))
+	for _, file := range files {
+		meta, err := readMetadata(file)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, meta)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+
+	return snapshots, nil
+}
+
+// readMetadata reads the metadata file of a snapshot.
+func readMetadata(file string) (snapshot, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("unable to read snapshot: %w", err)
+	}
+
+	var meta snapshot
+	if err := json.Unmarshal(contents, &meta); err != nil {
+		return snapshot{}, fmt.Errorf("unable to read snapshot %q: %w", filepath.Base(file), err)
+	}
+
+	return meta, nil
+}
+
+// removeSnapshot deletes both files of a snapshot.
+func removeSnapshot(name string) error {
+	dir, err := snapshotDir()
+	if err != nil {
+		return err
+	}
+
+	for _, ext := range []string{snapshotDataExt, snapshotMetadataExt} {
+		if err := os.Remove(filepath.Join(dir, name+ext)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to remove snapshot %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func snapshotsCommand(opts *settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "List the inputs saved with --save",
+		Long: `List the inputs saved with --save, which can be queried again with --from
+without re-running the command that produced them.`,
+		Example: sliceToTabulated(snapshotsExamples),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots, err := listSnapshots()
+			if err != nil {
+				return err
+			}
+
+			if len(snapshots) == 0 {
+				fmt.Fprintln(os.Stderr, "no snapshots saved yet, use --save to create one")
+				return nil
+			}
+
+			return printOutput(os.Stdout, snapshotColumns(snapshots, time.Now()), *opts)
+		},
+	}
+
+	cmd.AddCommand(snapshotsPruneCommand())
+	return cmd
+}
+
+func snapshotsPruneCommand() *cobra.Command {
+	var (
+		olderThan time.Duration
+		all       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune [names...]",
+		Short: "Remove saved snapshots, by name or by age",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && olderThan == 0 && !all {
+				return fmt.Errorf("either snapshot names, --older-than or --all are required to prune snapshots")
+			}
+
+			snapshots, err := listSnapshots()
+			if err != nil {
+				return err
+			}
+
+			names := make(map[string]bool, len(snapshots))
+			for _, snap := range snapshots {
+				names[snap.Name] = false
+			}
+
+			for _, name := range args {
+				if _, found := names[name]; !found {
+					return fmt.Errorf("snapshot %q does not exist", name)
+				}
+				names[name] = true
+			}
+
+			removed := 0
+			for _, snap := range snapshots {
+				if !all && !names[snap.Name] && (olderThan == 0 || time.Since(snap.Created) <= olderThan) {
+					continue
+				}
+
+				if err := removeSnapshot(snap.Name); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(os.Stderr, "removed snapshot %q\n", snap.Name)
+				removed++
+			}
+
+			fmt.Fprintf(os.Stderr, "%d snapshots removed\n", removed)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "remove snapshots older than this duration")
+	cmd.Flags().BoolVar(&all, "all", false, "remove all snapshots")
+
+	return cmd
+}
+
+// snapshotColumns converts the snapshot metadata into columns, so it can be
+// printed with any output format.
+func snapshotColumns(snapshots []snapshot, now time.Time) []tabloid.Column {
+	cols := []tabloid.Column{
+		{VisualPosition: 1, Title: "NAME", ExprTitle: "name"},
+		{VisualPosition: 2, Title: "CREATED", ExprTitle: "created"},
+		{VisualPosition: 3, Title: "AGE", ExprTitle: "age"},
+		{VisualPosition: 4, Title: "ROWS", ExprTitle: "rows"},
+		{VisualPosition: 5, Title: "COLUMNS", ExprTitle: "columns"},
+		{VisualPosition: 6, Title: "SOURCE", ExprTitle: "source"},
+	}
+
+	for _, snap := range snapshots {
+		source := snap.Source
+		if source == "" {
+			source = "<stdin>"
+		}
+
+		values := []string{
+			snap.Name,
+			snap.Created.Local().Format(time.RFC3339),
+			shortDuration(now.Sub(snap.Created)),
+			strconv.Itoa(snap.Rows),
+			strings.Join(snap.Columns, ","),
+			source,
+		}
+
+		for i := range cols {
+			cols[i].Values = append(cols[i].Values, values[i])
+		}
+	}
+
+	return cols
+}
+
+// shortDuration formats a duration using its largest unit, like kubectl
+// does for ages.
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// applySnapshot uses the options the --from snapshot was parsed with for
+// every parse flag that wasn't changed in the command line. Snapshots saved
+// before their options were recorded are parsed with the current ones.
+func applySnapshot(changed func(flag string) bool, opts *settings) error {
+	if opts.from == "" {
+		return nil
+	}
+
+	if err := validateSnapshotName(opts.from); err != nil {
+		return err
+	}
+
+	dir, err := snapshotDir()
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, opts.from+snapshotMetadataExt)
+	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	meta, err := readMetadata(file)
+	if err != nil {
+		return err
+	}
+
+	setString := func(flag string, dst *string, value string) {
+		if value != "" && !changed(flag) {
+			*dst = value
+		}
+	}
+
+	setString("input-format", &opts.inputFormat, meta.Parse.InputFormat)
+	setString("delimiter", &opts.delimiter, meta.Parse.Delimiter)
+	setString("profile", &opts.profile, meta.Parse.Profile)
+	setString("ansi", &opts.ansi, meta.Parse.ANSI)
+	if meta.Parse.TabWidth != 0 && !changed("tab-width") {
+		opts.tabWidth = meta.Parse.TabWidth
+	}
+
+	return nil
+}
+
+// readSnapshot copies the raw input of a snapshot into the writer, to be
+// parsed again.
+func readSnapshot(name string, w io.Writer) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+
+	dir, err := snapshotDir()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(dir, name+snapshotDataExt))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("snapshot %q does not exist, use \"tabloid snapshots\" to list them", name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to open snapshot %q: %w", name, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("unable to read snapshot %q: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_applySnapshot(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	opts := testSettings()
+	opts.inputFormat = "csv"
+	opts.save = "c1"
+
+	if err := run(strings.NewReader("name,ns\na,x\n"), &bytes.Buffer{}, opts); err != nil {
+		t.Fatalf("unable to save snapshot: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		changed map[string]bool
+		format  string
+		want    string
+	}{
+		{
+			name: "parse options are reused",
+			want: "name   ns   \na      x    \n",
+		},
+		{
+			name:    "flags given in the command line win",
+			changed: map[string]bool{"input-format": true},
+			format:  "table",
+			want:    "name,ns   \na,x       \n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testSettings()
+			opts.from = "c1"
+			opts.inputFormat = tt.format
+
+			if err := applySnapshot(func(flag string) bool { return tt.changed[flag] }, &opts); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var out bytes.Buffer
+			if err := run(nil, &out, opts); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runSource executes the given command, without a shell, and returns both
+// its standard output, to be used as tabloid's input, and its standard
+// error. If the command fails, its exit code and standard error are part of
+// the returned error.
+func runSource(ctx context.Context, args []string) (*bytes.Buffer, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		command := strings.Join(args, " ")
+		details := strings.TrimSpace(stderr.String())
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if details == "" {
+				return nil, "", fmt.Errorf("command %q exited with code %d", command, exitErr.ExitCode())
+			}
+
+			return nil, "", fmt.Errorf("command %q exited with code %d: %s", command, exitErr.ExitCode(), details)
+		}
+
+		return nil, "", fmt.Errorf("unable to run command %q: %w", command, err)
+	}
+
+	return &stdout, stderr.String(), nil
+}
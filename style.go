@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// tableStyle defines how a table is drawn.
+type tableStyle struct {
+	name        string
+	description string
+
+	// padding is the amount of spaces between columns or, in boxed styles,
+	// around every value.
+	padding int
+
+	// separator is drawn between columns instead of the padding in
+	// borderless styles, and between the borders of every column in boxed
+	// ones.
+	separator string
+
+	// boxed styles draw borders around every cell, using the vertical border
+	// on the edges and, from left to right, the corners, fill and junction
+	// characters of the horizontal borders.
+	boxed               bool
+	vertical            string
+	top, middle, bottom [4]string
+}
+
+// defaultStyle is the style used unless another one is requested, the same
+// used by kubectl and docker.
+var defaultStyle = tableStyle{
+	name:        "kubectl",
+	description: "aligned columns separated by 3 spaces, like kubectl and docker",
+	padding:     cellPadding,
+}
+
+// tableStyles are all the supported table styles.
+var tableStyles = []tableStyle{
+	defaultStyle,
+	{
+		name:        "compact",
+		description: "aligned columns separated by a single space",
+		padding:     1,
+	},
+	{
+		name:        "ascii",
+		description: "borders drawn with +, - and |",
+		padding:     1,
+		boxed:       true,
+		vertical:    "|",
+		separator:   "|",
+		top:         [4]string{"+", "-", "+", "+"},
+		middle:      [4]string{"+", "-", "+", "+"},
+		bottom:      [4]string{"+", "-", "+", "+"},
+	},
+	{
+		name:        "unicode",
+		description: "borders drawn with box-drawing characters",
+		padding:     1,
+		boxed:       true,
+		vertical:    "│",
+		separator:   "│",
+		top:         [4]string{"┌", "─", "┬", "┐"},
+		middle:      [4]string{"├", "─", "┼", "┤"},
+		bottom:      [4]string{"└", "─", "┴", "┘"},
+	},
+}
+
+// tableStyleNames returns the names of all the supported table styles.
+func tableStyleNames() []string {
+	names := make([]string, 0, len(tableStyles))
+	for _, s := range tableStyles {
+		names = append(names, s.name)
+	}
+
+	return names
+}
+
+// resolveStyle finds the table style requested, applying the custom padding
+// and separator, if any.
+func resolveStyle(opts *settings) error {
+	var found bool
+	for _, s := range tableStyles {
+		if s.name == opts.style {
+			opts.table, found = s, true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("invalid value for --style: unknown style %q, must be one of: %s", opts.style, strings.Join(tableStyleNames(), ", "))
+	}
+
+	if opts.padding < -1 {
+		return fmt.Errorf("invalid value for --padding: must be at least 0, got %d", opts.padding)
+	}
+
+	if opts.padding >= 0 {
+		opts.table.padding = opts.padding
+	}
+
+	if opts.separator != "" {
+		opts.table.separator = opts.separator
+	}
+
+	if opts.repeatTitles < 0 {
+		return fmt.Errorf("invalid value for --repeat-titles: must be at least 0, got %d", opts.repeatTitles)
+	}
+
+	return nil
+}
+
+// tableStyle returns the table style to use, which is the default one
+// unless another one was resolved.
+func (opts settings) tableStyle() tableStyle {
+	if opts.table.name == "" {
+		return defaultStyle
+	}
+
+	return opts.table
+}
+
+// gap returns what's drawn between two columns in borderless styles.
+func (s tableStyle) gap() string {
+	if s.separator != "" {
+		return s.separator
+	}
+
+	return strings.Repeat(" ", s.padding)
+}
+
+// overhead returns the width taken by everything but the values in a row
+// with the given amount of columns.
+func (s tableStyle) overhead(columns int) int {
+	if columns == 0 {
+		return 0
+	}
+
+	if !s.boxed {
+		return utf8.RuneCountInString(s.gap()) * (columns - 1)
+	}
+
+	edges := utf8.RuneCountInString(s.vertical) * 2
+	return edges + 2*s.padding*columns + utf8.RuneCountInString(s.separator)*(columns-1)
+}
+
+// border returns a horizontal border for columns of the given widths, or an
+// empty string if the style doesn't draw it.
+func (s tableStyle) border(chars [4]string, widths []int) string {
+	if !s.boxed || chars[1] == "" {
+		return ""
+	}
+
+	junction := chars[2]
+	if n := utf8.RuneCountInString(s.separator); n != 1 {
+		junction = strings.Repeat(chars[1], n)
+	}
+
+	cells := make([]string, 0, len(widths))
+	for _, w := range widths {
+		cells = append(cells, strings.Repeat(chars[1], w+2*s.padding))
+	}
+
+	return chars[0] + strings.Join(cells, junction) + chars[3] + "\n"
+}
@@ -0,0 +1,153 @@
+package tabloid
+
+import "strings"
+
+// ANSIReset is the escape sequence that resets all terminal styles, added
+// after styled values.
+const ANSIReset = "\033[0m"
+
+// KeepANSI enables keeping the original terminal styling of every value, in
+// the Styled field of each column. Escape sequences are always removed from
+// the values used to find columns and evaluate expressions.
+func (t *Tabloid) KeepANSI(enable bool) {
+	t.keepANSI = enable
+}
+
+// StripANSI removes all terminal escape sequences from a string, like the
+// ones used for colors or hyperlinks.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\033") {
+		return s
+	}
+
+	return parseStyled(s).plain
+}
+
+// styledLine is a line without its escape sequences, remembering where they
+// were, so the styled text of any part of the line can be recovered.
+type styledLine struct {
+	raw   string
+	plain string
+
+	// starts maps every byte of the plain line, and its end, to the position
+	// in the raw line right after the previous byte, so it includes any
+	// escape sequences before it.
+	starts []int
+
+	// active holds, for every byte of the plain line, the styles set before
+	// the escape sequences preceding it and not reset since.
+	active []string
+}
+
+// parseStyled removes the escape sequences of a line, keeping track of them.
+// Both CSI sequences, like colors, and OSC sequences, like hyperlinks, are
+// removed.
+func parseStyled(raw string) styledLine {
+	line := styledLine{raw: raw}
+	if !strings.Contains(raw, "\033") {
+		line.plain = raw
+		return line
+	}
+
+	var (
+		plain strings.Builder
+
+		// active are the styles set and not reset so far, and previous are
+		// the ones that were active right after the last byte written.
+		active, previous string
+		start            int
+	)
+
+	for i := 0; i < len(raw); {
+		if size := escapeLength(raw[i:]); size > 0 {
+			if seq := raw[i : i+size]; strings.HasSuffix(seq, "m") && strings.HasPrefix(seq, "\033[") {
+				if seq == ANSIReset || seq == "\033[m" {
+					active = ""
+				} else {
+					active += seq
+				}
+			}
+
+			i += size
+			continue
+		}
+
+		line.starts = append(line.starts, start)
+		line.active = append(line.active, previous)
+		plain.WriteByte(raw[i])
+		i++
+		start, previous = i, active
+	}
+
+	line.starts = append(line.starts, start)
+	line.plain = plain.String()
+	return line
+}
+
+// escapeLength returns the length of the escape sequence at the start of s,
+// or 0 if there's none.
+func escapeLength(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+
+	return 2
+}
+
+// styled returns the styled text of the plain line between the given
+// positions, with the styles active at its start, and reset at its end if
+// it contains any.
+func (l styledLine) styled(start, end int) string {
+	if l.starts == nil {
+		return l.plain[start:end]
+	}
+
+	text := l.active[start] + l.raw[l.starts[start]:l.starts[end]]
+	if strings.Contains(text, "\033") {
+		text += ANSIReset
+	}
+
+	return text
+}
+
+// styledValues finds the given values, in order, in the plain line, and
+// returns their styled text. Values that can't be found, because they were
+// changed while parsing them, are returned as is.
+func (l styledLine) styledValues(values []string) []string {
+	styled := make([]string, 0, len(values))
+	cursor := 0
+
+	for _, v := range values {
+		pos := strings.Index(l.plain[cursor:], v)
+		if v == "" || pos == -1 {
+			styled = append(styled, v)
+			continue
+		}
+
+		start := cursor + pos
+		cursor = start + len(v)
+		styled = append(styled, l.styled(start, cursor))
+	}
+
+	return styled
+}
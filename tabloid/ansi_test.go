@@ -0,0 +1,94 @@
+package tabloid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain", input: "Running", want: "Running"},
+		{name: "colors", input: "\033[1;32mRunning\033[0m", want: "Running"},
+		{name: "hyperlinks", input: "\033]8;;https://example.com\033\\web\033]8;;\033\\", want: "web"},
+		{name: "cursor movement", input: "\033[2Kweb\033[1A", want: "web"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StripANSI(tt.input)
+			assertEqual(t, got, tt.want, "StripANSI(%q) = %q, want %q", tt.input, got, tt.want)
+		})
+	}
+}
+
+func TestTabloid_ParseColumns_ansi(t *testing.T) {
+	input := "\033[1mNAME\033[0m    \033[1mSTATUS\033[0m    AGE\n" +
+		"web-1   \033[32mRunning\033[0m   8d\n" +
+		"\033[33mweb-2   Pending   2d\033[0m\n"
+
+	tests := []struct {
+		name   string
+		keep   bool
+		values map[string][]string
+		styled map[string][]string
+	}{
+		{
+			name: "stripped",
+			values: map[string][]string{
+				"name":   {"web-1", "web-2"},
+				"status": {"Running", "Pending"},
+				"age":    {"8d", "2d"},
+			},
+			styled: map[string][]string{"status": nil},
+		},
+		{
+			name: "kept",
+			keep: true,
+			values: map[string][]string{
+				"status": {"Running", "Pending"},
+			},
+			styled: map[string][]string{
+				"name":   {"web-1", "\033[33mweb-2\033[0m"},
+				"status": {"\033[32mRunning\033[0m", "\033[33mPending\033[0m"},
+				"age":    {"8d", "\033[33m2d\033[0m"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := New(bytes.NewBufferString(input))
+			tab.KeepANSI(tt.keep)
+
+			got, err := tab.ParseColumns()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			byTitle := make(map[string]Column, len(got))
+			for _, c := range got {
+				byTitle[c.ExprTitle] = c
+			}
+
+			for title, want := range tt.values {
+				assertEqual(t, byTitle[title].Values, want, "column %q values = %q, want %q", title, byTitle[title].Values, want)
+			}
+
+			for title, want := range tt.styled {
+				assertEqual(t, byTitle[title].Styled, want, "column %q styled values = %q, want %q", title, byTitle[title].Styled, want)
+			}
+
+			filtered, err := tab.Filter(got, `status == "Pending"`)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if tt.keep {
+				want := []string{"\033[33mPending\033[0m"}
+				assertEqual(t, filtered[1].Styled, want, "filtered styled values = %q, want %q", filtered[1].Styled, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package tabloid
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// reRowCountFooter matches the row count printed after a table by psql.
+var reRowCountFooter = regexp.MustCompile(`^\(\d+ rows?\)$`)
+
+// reDelimiterCell matches a cell of the line below the titles of a Markdown
+// table, with its optional alignment colons.
+var reDelimiterCell = regexp.MustCompile(`^:?-+:?$`)
+
+// isCellSeparator reports whether a rune separates the cells of a row in
+// a box-drawn or Markdown table.
+func isCellSeparator(r rune) bool {
+	switch r {
+	case '|', '│', '┃', '║':
+		return true
+	}
+
+	return false
+}
+
+// isBoxDrawing reports whether a rune is one of the Unicode box-drawing
+// characters.
+func isBoxDrawing(r rune) bool {
+	return r >= '─' && r <= '╿'
+}
+
+// isBorder reports whether a line is only made of border characters, like
+// "+----+------+", "├────┼──────┤" or the "|---|:--:|" line below the
+// titles of a Markdown table. Borders have no spaces between their
+// characters, so rows whose values are all dashes, like "| -  | -  |",
+// are not borders.
+func isBorder(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+
+	horizontal := false
+	for _, r := range line {
+		switch {
+		case isCellSeparator(r), r == '+', r == ':':
+		case r == '-', r == '=', isBoxDrawing(r):
+			horizontal = true
+		default:
+			return false
+		}
+	}
+
+	return horizontal
+}
+
+// isDelimiterRow reports whether a line is the one below the titles of a
+// Markdown table, like "| --- | :---: |", whose cells may be padded with
+// spaces.
+func isDelimiterRow(line string) bool {
+	if !hasCellSeparator(line) {
+		return false
+	}
+
+	for _, cell := range splitCells(line) {
+		if !reDelimiterCell.MatchString(cell) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasCellSeparator reports whether a line contains a cell separator.
+func hasCellSeparator(line string) bool {
+	return strings.IndexFunc(line, isCellSeparator) != -1
+}
+
+// splitCells splits a row of a box-drawn or Markdown table into its cells,
+// ignoring the outer borders and trimming every value. Pipes escaped with a
+// backslash, as used in Markdown, are kept as part of the value, and so are
+// all pipes in rows drawn with Unicode characters.
+func splitCells(line string) []string {
+	line = strings.TrimSpace(line)
+
+	isSeparator := isCellSeparator
+	if strings.ContainsAny(line, "│┃║") {
+		isSeparator = func(r rune) bool { return r != '|' && isCellSeparator(r) }
+	}
+
+	if r, size := utf8.DecodeRuneInString(line); isSeparator(r) {
+		line = line[size:]
+	}
+
+	if r, size := utf8.DecodeLastRuneInString(line); isSeparator(r) && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-size]
+	}
+
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+
+	for i, r := range line {
+		if r == '|' && i > 0 && line[i-1] == '\\' {
+			current := cell.String()
+			cell.Reset()
+			cell.WriteString(strings.TrimSuffix(current, `\`))
+			cell.WriteRune(r)
+			continue
+		}
+
+		if isSeparator(r) {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+
+		cell.WriteRune(r)
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// detectBoxed reports whether the input starts with a box-drawn or
+// Markdown table: either its first line is a border or starts with a cell
+// separator, or its titles are separated by cell separators and followed by
+// a border, like the tables printed by psql.
+func detectBoxed(input []byte) bool {
+	var lines []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(input))
+	for len(lines) < 2 && scanner.Scan() {
+		if line := StripANSI(scanner.Text()); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) == 0 {
+		return false
+	}
+
+	first := strings.TrimSpace(lines[0])
+	if r, _ := utf8.DecodeRuneInString(first); isBorder(first) || isCellSeparator(r) {
+		return true
+	}
+
+	return len(lines) == 2 && hasCellSeparator(first) && isBorder(lines[1])
+}
+
+// isBoxed reports whether the input is read as a box-drawn or Markdown
+// table, either because it was requested or because it was detected.
+func (t *Tabloid) isBoxed() bool {
+	return t.format == InputBox || t.boxed
+}
+
+// startsBoxed reports whether a heading is the first line of a box-drawn or
+// Markdown table.
+func startsBoxed(heading string) bool {
+	r, _ := utf8.DecodeRuneInString(strings.TrimSpace(heading))
+	return isBorder(heading) || isCellSeparator(r)
+}
+
+// SkipLine reports whether a line is not a row and should be skipped, like
+// empty lines, the borders of a box-drawn or Markdown table, or the row
+// count psql prints after its tables. Lines before the heading are checked
+// too, since box-drawn tables start with a border. Markdown delimiter rows
+// padded with spaces are only skipped right after the heading, where they
+// can't be confused with rows of dashes.
+func (t *Tabloid) SkipLine(line string) bool {
+	line = StripANSI(line)
+	if strings.TrimSpace(line) == "" {
+		return true
+	}
+
+	if t.isCSV() {
+		return false
+	}
+
+	if !t.boxed && !t.parsedHeading && t.readsTable() && startsBoxed(line) {
+		t.boxed = true
+	}
+
+	if !t.isBoxed() {
+		return false
+	}
+
+	if isBorder(line) {
+		return true
+	}
+
+	if t.parsedHeading && !t.readRows && isDelimiterRow(line) {
+		return true
+	}
+
+	// Rows of tables whose titles are separated by cell separators always
+	// have them too, so lines without any, like psql's row count, are not
+	// rows.
+	if t.cellSeparated && !hasCellSeparator(line) {
+		return true
+	}
+
+	if t.parsedHeading && reRowCountFooter.MatchString(strings.TrimSpace(line)) {
+		return true
+	}
+
+	t.readRows = t.parsedHeading
+	return false
+}
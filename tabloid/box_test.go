@@ -0,0 +1,152 @@
+package tabloid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTabloid_ParseColumns_box(t *testing.T) {
+	want := map[string][]string{
+		"id":     {"1", "2"},
+		"name":   {"web", "a|b"},
+		"status": {"up", ""},
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		format InputFormat
+		want   map[string][]string
+	}{
+		{
+			name: "ascii borders",
+			input: "+----+------+--------+\n" +
+				"| id | name | status |\n" +
+				"+----+------+--------+\n" +
+				"|  1 | web  | up     |\n" +
+				"|  2 | a\\|b |        |\n" +
+				"+----+------+--------+\n",
+			want: want,
+		},
+		{
+			name: "unicode borders",
+			input: "┌────┬──────┬────────┐\n" +
+				"│ id │ name │ status │\n" +
+				"├────┼──────┼────────┤\n" +
+				"│ 1  │ web  │ up     │\n" +
+				"│ 2  │ a|b  │        │\n" +
+				"└────┴──────┴────────┘\n",
+			want: want,
+		},
+		{
+			name: "markdown",
+			input: "| id | name | status |\n" +
+				"|---:|:-----|--------|\n" +
+				"| 1 | web | up |\n" +
+				"| 2 | a\\|b | |\n",
+			want: want,
+		},
+		{
+			name: "psql with row count",
+			input: " id | name | status\n" +
+				"----+------+--------\n" +
+				"  1 | web  | up\n" +
+				"  2 | api  |\n" +
+				"(2 rows)\n\n",
+			want: map[string][]string{
+				"id":     {"1", "2"},
+				"name":   {"web", "api"},
+				"status": {"up", ""},
+			},
+		},
+		{
+			name:   "psql with a single column",
+			format: InputBox,
+			input:  " id\n----\n  1\n  2\n(2 rows)\n",
+			want:   map[string][]string{"id": {"1", "2"}},
+		},
+		{
+			name: "rows of dashes in borders are values",
+			input: "+----+------+\n" +
+				"| id | name |\n" +
+				"+----+------+\n" +
+				"| 1  | web  |\n" +
+				"| -  | -    |\n" +
+				"| 3  | ==   |\n" +
+				"+----+------+\n",
+			want: map[string][]string{
+				"id":   {"1", "-", "3"},
+				"name": {"web", "-", "=="},
+			},
+		},
+		{
+			name: "markdown with padded delimiter row and rows of dashes",
+			input: "| id | name |\n" +
+				"| --- | :---: |\n" +
+				"| 1 | web |\n" +
+				"| - | --- |\n",
+			want: map[string][]string{
+				"id":   {"1", "-"},
+				"name": {"web", "---"},
+			},
+		},
+		{
+			name:  "dashes in a table are values",
+			input: "NAME   AGE\nweb    -\n",
+			want: map[string][]string{
+				"name": {"web"},
+				"age":  {"-"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := New(bytes.NewBufferString(tt.input))
+			if tt.format != "" {
+				tab.SetInputFormat(tt.format)
+			}
+
+			got, err := tab.ParseColumns()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertEqual(t, len(got), len(tt.want), "got %d columns, want %d", len(got), len(tt.want))
+
+			for _, c := range got {
+				assertEqual(t, c.Values, tt.want[c.ExprTitle], "column %q values = %q, want %q", c.Title, c.Values, tt.want[c.ExprTitle])
+			}
+		})
+	}
+}
+
+func TestTabloid_SkipLine_box(t *testing.T) {
+	lines := []struct {
+		line string
+		skip bool
+	}{
+		{line: "", skip: true},
+		{line: "+----+------+", skip: true},
+		{line: "| id | name |"},
+		{line: "+====+======+", skip: true},
+		{line: "|  1 | web  |"},
+		{line: "| -  | -    |"},
+		{line: "| -- | :--: |"},
+		{line: "+----+------+", skip: true},
+	}
+
+	tab := New(nil)
+	for _, l := range lines {
+		if l.skip {
+			assertEqual(t, tab.SkipLine(l.line), true, "line %q must be skipped", l.line)
+			continue
+		}
+
+		assertEqual(t, tab.SkipLine(l.line), false, "line %q must not be skipped", l.line)
+		if !tab.parsedHeading {
+			if _, err := tab.ParseHeading(l.line); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+	}
+}
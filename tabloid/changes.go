@@ -0,0 +1,179 @@
+package tabloid
+
+import (
+	"fmt"
+	"time"
+)
+
+// Change events reported by a ChangeFeed.
+const (
+	EventAdded   = "added"
+	EventChanged = "changed"
+	EventRemoved = "removed"
+)
+
+// ChangeFeed keeps track of rows, identified by the values of one or more
+// key columns, to report which ones were added, changed or removed between
+// snapshots of the same dataset.
+type ChangeFeed struct {
+	titles  []Column
+	rows    map[string]changeRow
+	ordered []string
+}
+
+// changeRow is a row known to a ChangeFeed, with the plain and styled text
+// of its values.
+type changeRow struct {
+	values []string
+	styled []string
+}
+
+// NewChangeFeed creates an empty ChangeFeed.
+func NewChangeFeed() *ChangeFeed {
+	return &ChangeFeed{rows: make(map[string]changeRow)}
+}
+
+// Snapshot compares a full snapshot of the dataset against the previous one
+// and returns the rows that are new, changed or no longer present. Rows are
+// identified by the values of the key columns in the same row, which must
+// have as many rows as the columns, but don't need to be part of them.
+func (f *ChangeFeed) Snapshot(columns, keys []Column, at time.Time) ([]Column, error) {
+	return f.compare(columns, keys, at, true)
+}
+
+// Update compares a partial snapshot of the dataset, like a single row from
+// a stream, against the known rows and returns the rows that are new or
+// changed. Rows not present in the update are kept as they are.
+func (f *ChangeFeed) Update(columns, keys []Column, at time.Time) ([]Column, error) {
+	return f.compare(columns, keys, at, false)
+}
+
+func (f *ChangeFeed) compare(columns, keys []Column, at time.Time, full bool) ([]Column, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one key column is required to track changes")
+	}
+
+	if rowCount(keys) != rowCount(columns) {
+		return nil, fmt.Errorf("key columns have %d rows, but the columns have %d", rowCount(keys), rowCount(columns))
+	}
+
+	var (
+		events   []string
+		rows     []changeRow
+		seen     = make(map[string]struct{}, rowCount(columns))
+		previous = f.titles
+	)
+
+	for pos := 0; pos < rowCount(columns); pos++ {
+		row := changeRow{
+			values: make([]string, 0, len(columns)),
+			styled: make([]string, 0, len(columns)),
+		}
+		for _, c := range columns {
+			row.values = append(row.values, c.Values[pos])
+			row.styled = append(row.styled, c.styledValue(pos))
+		}
+
+		key := rowKey(keys, pos)
+		seen[key] = struct{}{}
+
+		before, found := f.rows[key]
+		switch {
+		case !found:
+			events = append(events, EventAdded)
+			rows = append(rows, row)
+			f.ordered = append(f.ordered, key)
+		case !equalValues(before.values, row.values):
+			events = append(events, EventChanged)
+			rows = append(rows, row)
+		}
+
+		f.rows[key] = row
+	}
+
+	var removed []changeRow
+	if full {
+		kept := make([]string, 0, len(f.ordered))
+		for _, key := range f.ordered {
+			if _, found := seen[key]; found {
+				kept = append(kept, key)
+				continue
+			}
+
+			removed = append(removed, f.rows[key])
+			delete(f.rows, key)
+		}
+		f.ordered = kept
+	}
+
+	f.titles = make([]Column, 0, len(columns))
+	for _, c := range columns {
+		c.Values, c.Styled = nil, nil
+		f.titles = append(f.titles, c)
+	}
+
+	// Removed rows are reported with the columns they had when last seen,
+	// matched by title against the current columns.
+	for _, row := range removed {
+		current := changeRow{values: make([]string, len(columns)), styled: make([]string, len(columns))}
+		for i, c := range columns {
+			for j, p := range previous {
+				if p.ExprTitle == c.ExprTitle && j < len(row.values) {
+					current.values[i], current.styled[i] = row.values[j], row.styled[j]
+					break
+				}
+			}
+		}
+
+		events = append(events, EventRemoved)
+		rows = append(rows, current)
+	}
+
+	timestamp := at.Format(time.RFC3339)
+	result := []Column{
+		{VisualPosition: 1, Title: "EVENT", ExprTitle: "event", Values: make([]string, 0, len(rows))},
+		{VisualPosition: 2, Title: "TIMESTAMP", ExprTitle: "timestamp", Values: make([]string, 0, len(rows))},
+	}
+
+	for i := range rows {
+		result[0].Values = append(result[0].Values, events[i])
+		result[1].Values = append(result[1].Values, timestamp)
+	}
+
+	for i, c := range columns {
+		c.VisualPosition = len(result) + 1
+		c.Values = make([]string, 0, len(rows))
+		c.Styled = nil
+
+		styled := make([]string, 0, len(rows))
+		hasStyles := false
+		for _, row := range rows {
+			c.Values = append(c.Values, row.values[i])
+			styled = append(styled, row.styled[i])
+			hasStyles = hasStyles || row.styled[i] != row.values[i]
+		}
+
+		// Styles are only kept when there are any, like in parsed columns.
+		if hasStyles {
+			c.Styled = styled
+		}
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+// equalValues reports whether two rows have the same values.
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
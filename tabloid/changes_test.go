@@ -0,0 +1,199 @@
+package tabloid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangeFeed(t *testing.T) {
+	snapshot := func(names, statuses []string) []Column {
+		return []Column{
+			{Title: "NAME", ExprTitle: "name", Values: names},
+			{Title: "STATUS", ExprTitle: "status", Values: statuses},
+		}
+	}
+
+	feed := NewChangeFeed()
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name     string
+		columns  []Column
+		full     bool
+		events   []string
+		names    []string
+		statuses []string
+	}{
+		{
+			name:     "initial snapshot",
+			columns:  snapshot([]string{"web", "db"}, []string{"Pending", "Running"}),
+			full:     true,
+			events:   []string{EventAdded, EventAdded},
+			names:    []string{"web", "db"},
+			statuses: []string{"Pending", "Running"},
+		},
+		{
+			name:     "changed, added and removed rows",
+			columns:  snapshot([]string{"web", "cache"}, []string{"Running", "Running"}),
+			full:     true,
+			events:   []string{EventChanged, EventAdded, EventRemoved},
+			names:    []string{"web", "cache", "db"},
+			statuses: []string{"Running", "Running", "Running"},
+		},
+		{
+			name:     "no changes",
+			columns:  snapshot([]string{"web", "cache"}, []string{"Running", "Running"}),
+			full:     true,
+			events:   []string{},
+			names:    []string{},
+			statuses: []string{},
+		},
+		{
+			name:     "partial update does not remove rows",
+			columns:  snapshot([]string{"cache"}, []string{"Terminating"}),
+			events:   []string{EventChanged},
+			names:    []string{"cache"},
+			statuses: []string{"Terminating"},
+		},
+	}
+
+	for _, step := range steps {
+		compare := feed.Update
+		if step.full {
+			compare = feed.Snapshot
+		}
+
+		got, err := compare(step.columns, step.columns[:1], at)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", step.name, err)
+		}
+
+		assertEqual(t, titles(got), []string{"EVENT", "TIMESTAMP", "NAME", "STATUS"}, "%s: titles = %q", step.name, titles(got))
+		assertEqual(t, got[0].Values, step.events, "%s: events = %q, want %q", step.name, got[0].Values, step.events)
+		assertEqual(t, got[2].Values, step.names, "%s: names = %q, want %q", step.name, got[2].Values, step.names)
+		assertEqual(t, got[3].Values, step.statuses, "%s: statuses = %q, want %q", step.name, got[3].Values, step.statuses)
+	}
+
+	columns := snapshot([]string{"web"}, []string{"Running"})
+	if _, err := feed.Snapshot(columns, nil, at); err == nil {
+		t.Errorf("expected error without key columns")
+	}
+
+	keys := []Column{{Title: "ID", ExprTitle: "id", Values: []string{"1", "2"}}}
+	if _, err := feed.Snapshot(columns, keys, at); err == nil {
+		t.Errorf("expected error for key columns with a different amount of rows")
+	}
+}
+
+func TestChangeFeed_keysNotDisplayed(t *testing.T) {
+	feed := NewChangeFeed()
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	names := []Column{{Title: "NAME", ExprTitle: "name", Values: []string{"web", "db"}}}
+	statuses := func(values ...string) []Column {
+		return []Column{{Title: "STATUS", ExprTitle: "status", Values: values}}
+	}
+
+	if _, err := feed.Snapshot(statuses("Running", "Running"), names, at); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := feed.Snapshot(statuses("Running", "Error"), names, at)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assertEqual(t, titles(got), []string{"EVENT", "TIMESTAMP", "STATUS"}, "titles = %q", titles(got))
+	assertEqual(t, got[0].Values, []string{EventChanged}, "events = %q", got[0].Values)
+	assertEqual(t, got[2].Values, []string{"Error"}, "statuses = %q", got[2].Values)
+}
+
+func TestChangeFeed_styled(t *testing.T) {
+	feed := NewChangeFeed()
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	snapshot := func(names, styled []string) []Column {
+		return []Column{{Title: "NAME", ExprTitle: "name", Values: names, Styled: styled}}
+	}
+
+	steps := []struct {
+		name       string
+		columns    []Column
+		events     []string
+		wantStyled []string
+	}{
+		{
+			name:       "initial snapshot",
+			columns:    snapshot([]string{"a", "b"}, []string{"\x1b[32ma\x1b[0m", "\x1b[31mb\x1b[0m"}),
+			events:     []string{EventAdded, EventAdded},
+			wantStyled: []string{"\x1b[32ma\x1b[0m", "\x1b[31mb\x1b[0m"},
+		},
+		{
+			name:       "added and removed rows keep their own styles",
+			columns:    snapshot([]string{"b", "c"}, []string{"\x1b[31mb\x1b[0m", "\x1b[33mc\x1b[0m"}),
+			events:     []string{EventAdded, EventRemoved},
+			wantStyled: []string{"\x1b[33mc\x1b[0m", "\x1b[32ma\x1b[0m"},
+		},
+		{
+			name:    "unstyled rows have no styles",
+			columns: snapshot([]string{"b", "c", "d"}, nil),
+			events:  []string{EventAdded},
+		},
+	}
+
+	for _, step := range steps {
+		got, err := feed.Snapshot(step.columns, step.columns, at)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", step.name, err)
+		}
+
+		assertEqual(t, got[0].Values, step.events, "%s: events = %q, want %q", step.name, got[0].Values, step.events)
+		assertEqual(t, got[2].Styled, step.wantStyled, "%s: styled = %q, want %q", step.name, got[2].Styled, step.wantStyled)
+	}
+}
+
+func TestChangeFeed_streamedRows(t *testing.T) {
+	// Every line of "kubectl get pods --watch" is aligned to its own widths.
+	lines := []string{
+		"NAME                     READY   STATUS              RESTARTS   AGE",
+		"frontend-5c6c94684f-5k   1/1     Running             0          8d",
+		"redis-abc   0/1   Pending   0     0s",
+		"redis-abc   0/1   ContainerCreating   0     1s",
+		"redis-abc   1/1   Running   1 (2s ago)   12s",
+	}
+
+	tab := New(nil)
+	heading, err := tab.ParseHeading(lines[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	feed := NewChangeFeed()
+
+	want := []struct {
+		event  string
+		values []string
+	}{
+		{EventAdded, []string{"frontend-5c6c94684f-5k", "1/1", "Running", "0", "8d"}},
+		{EventAdded, []string{"redis-abc", "0/1", "Pending", "0", "0s"}},
+		{EventChanged, []string{"redis-abc", "0/1", "ContainerCreating", "0", "1s"}},
+		{EventChanged, []string{"redis-abc", "1/1", "Running", "1 (2s ago)", "12s"}},
+	}
+
+	for i, line := range lines[1:] {
+		row := tab.ParseRow(heading, line)
+
+		values := make([]string, 0, len(row))
+		for _, c := range row {
+			values = append(values, c.Values[0])
+		}
+		assertEqual(t, values, want[i].values, "line %d: values = %q, want %q", i+1, values, want[i].values)
+
+		events, err := feed.Update(row, row[:1], time.Now())
+		if err != nil {
+			t.Fatalf("line %d: unexpected error: %s", i+1, err)
+		}
+
+		assertEqual(t, events[0].Values, []string{want[i].event}, "line %d: events = %q, want %q", i+1, events[0].Values, want[i].event)
+	}
+}
@@ -0,0 +1,270 @@
+package tabloid
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+)
+
+// styleCodes are the SGR codes of the style names supported in color rules.
+var styleCodes = map[string]string{
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"reverse":   "7",
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+}
+
+// reStyleCode matches a raw SGR code, like "38;5;208".
+var reStyleCode = regexp.MustCompile(`^\d+(;\d+)*$`)
+
+// matchStart and matchEnd surround the parts of a value matched by a
+// regular expression, using reverse video so any other style is kept.
+const (
+	matchStart = "\033[7m"
+	matchEnd   = "\033[27m"
+)
+
+// ColorRule styles the cells of the columns used by an expression, in the
+// rows where it evaluates to true.
+type ColorRule struct {
+	Expression string
+
+	// Style is the escape sequence that sets the style.
+	Style string
+}
+
+// StyleNames returns the names of the styles that can be used in color
+// rules, sorted.
+func StyleNames() []string {
+	names := make([]string, 0, len(styleCodes))
+	for name := range styleCodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseStyle converts a style, like "red" or "bold+yellow", into its escape
+// sequence. Raw SGR codes, like "38;5;208", are supported too.
+func ParseStyle(s string) (string, error) {
+	var codes []string
+
+	for _, name := range strings.Split(s, "+") {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if code, found := styleCodes[name]; found {
+			codes = append(codes, code)
+			continue
+		}
+
+		if reStyleCode.MatchString(name) {
+			codes = append(codes, name)
+			continue
+		}
+
+		return "", fmt.Errorf("unknown style %q, must be a raw SGR code or one of: %s", name, strings.Join(StyleNames(), ", "))
+	}
+
+	return "\033[" + strings.Join(codes, ";") + "m", nil
+}
+
+// ParseColorRule parses a rule in the form of expression:style, like
+// `status == "Running":green`.
+func ParseColorRule(s string) (ColorRule, error) {
+	pos := strings.LastIndex(s, ":")
+	if pos == -1 {
+		return ColorRule{}, fmt.Errorf("invalid color rule %q: must be in the form of expression:style", s)
+	}
+
+	expression := strings.TrimSpace(s[:pos])
+	if _, err := govaluate.NewEvaluableExpressionWithFunctions(expression, funcs); err != nil {
+		return ColorRule{}, fmt.Errorf("unable to process expression %q in color rule: %w", expression, err)
+	}
+
+	style, err := ParseStyle(s[pos+1:])
+	if err != nil {
+		return ColorRule{}, fmt.Errorf("invalid color rule %q: %w", s, err)
+	}
+
+	return ColorRule{Expression: expression, Style: style}, nil
+}
+
+// Colorize styles the cells of every row matching a rule, by setting their
+// styled values. Only the cells of the columns used by the expression are
+// styled, or the whole row if it uses none. Cells matching several rules
+// combine their styles.
+func (t *Tabloid) Colorize(columns []Column, rules []ColorRule) ([]Column, error) {
+	if len(rules) == 0 || rowCount(columns) == 0 {
+		return columns, nil
+	}
+
+	styles := make([][]string, len(columns))
+
+	for _, rule := range rules {
+		expr, err := govaluate.NewEvaluableExpressionWithFunctions(rule.Expression, funcs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to process expression %q: %w", rule.Expression, err)
+		}
+
+		targets := usedColumns(columns, expr.Vars())
+
+		for pos := 0; pos < rowCount(columns); pos++ {
+			result, err := expr.Evaluate(rowValues(columns, pos))
+			if err != nil {
+				return nil, fmt.Errorf("unable to evaluate color rule %q for row %d: %w", rule.Expression, pos+1, err)
+			}
+
+			if matched, ok := result.(bool); !ok || !matched {
+				continue
+			}
+
+			for _, col := range targets {
+				if styles[col] == nil {
+					styles[col] = make([]string, rowCount(columns))
+				}
+				styles[col][pos] += rule.Style
+			}
+		}
+	}
+
+	colored := make([]Column, len(columns))
+	for col, column := range columns {
+		if styles[col] != nil {
+			styled := make([]string, 0, len(column.Values))
+			for pos, style := range styles[col] {
+				text := column.styledValue(pos)
+				if style != "" {
+					text = style + text + ANSIReset
+				}
+				styled = append(styled, text)
+			}
+			column.Styled = styled
+		}
+
+		colored[col] = column
+	}
+
+	return colored, nil
+}
+
+// HighlightMatches highlights, inside every cell, the text matched by the
+// regular expressions the expression compares the cell's column with using
+// the "=~" operator.
+func (t *Tabloid) HighlightMatches(columns []Column, expression string) ([]Column, error) {
+	if strings.TrimSpace(expression) == "" {
+		return columns, nil
+	}
+
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(expression, funcs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process expression %q: %w", expression, err)
+	}
+
+	patterns := make(map[string][]*regexp.Regexp)
+	tokens := expr.Tokens()
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].Kind != govaluate.VARIABLE || tokens[i+1].Kind != govaluate.COMPARATOR || tokens[i+1].Value != "=~" {
+			continue
+		}
+
+		if re, ok := tokens[i+2].Value.(*regexp.Regexp); ok {
+			name := tokens[i].Value.(string)
+			patterns[name] = append(patterns[name], re)
+		}
+	}
+
+	if len(patterns) == 0 {
+		return columns, nil
+	}
+
+	highlighted := make([]Column, len(columns))
+	for col, column := range columns {
+		if res, found := patterns[column.ExprTitle]; found && len(column.Styled) == 0 {
+			styled := make([]string, 0, len(column.Values))
+			for _, value := range column.Values {
+				styled = append(styled, highlightValue(value, res))
+			}
+			column.Styled = styled
+		}
+
+		highlighted[col] = column
+	}
+
+	return highlighted, nil
+}
+
+// highlightValue surrounds the parts of a value matched by any of the
+// regular expressions with the match style.
+func highlightValue(value string, res []*regexp.Regexp) string {
+	marked := make([]bool, len(value))
+	for _, re := range res {
+		for _, loc := range re.FindAllStringIndex(value, -1) {
+			for i := loc[0]; i < loc[1]; i++ {
+				marked[i] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(matchStart)
+		}
+
+		b.WriteByte(value[i])
+
+		if marked[i] && (i == len(value)-1 || !marked[i+1]) {
+			b.WriteString(matchEnd)
+		}
+	}
+
+	return b.String()
+}
+
+// usedColumns returns the positions of the columns with the given normalized
+// titles, or of all of them if none is found.
+func usedColumns(columns []Column, exprTitles []string) []int {
+	var positions []int
+	for pos, c := range columns {
+		for _, title := range exprTitles {
+			if c.ExprTitle == title {
+				positions = append(positions, pos)
+				break
+			}
+		}
+	}
+
+	if len(positions) > 0 {
+		return positions
+	}
+
+	for pos := range columns {
+		positions = append(positions, pos)
+	}
+
+	return positions
+}
+
+// styledValue returns the styled text of the value in the given row, or the
+// value itself if it has none.
+func (c Column) styledValue(pos int) string {
+	if pos < len(c.Styled) {
+		return c.Styled[pos]
+	}
+
+	return c.Values[pos]
+}
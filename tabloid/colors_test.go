@@ -0,0 +1,105 @@
+package tabloid
+
+import "testing"
+
+func TestParseColorRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    ColorRule
+		wantErr bool
+	}{
+		{
+			name: "named color",
+			rule: `status == "Running":green`,
+			want: ColorRule{Expression: `status == "Running"`, Style: "\033[32m"},
+		},
+		{
+			name: "combined styles",
+			rule: `isnotready(ready):bold+Red`,
+			want: ColorRule{Expression: `isnotready(ready)`, Style: "\033[1;31m"},
+		},
+		{
+			name: "colons in the expression",
+			rule: `age == "1:30":38;5;208`,
+			want: ColorRule{Expression: `age == "1:30"`, Style: "\033[38;5;208m"},
+		},
+		{
+			name:    "missing style",
+			rule:    `status == "Running"`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown style",
+			rule:    `status == "Running":purple`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid expression",
+			rule:    `status == :green`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseColorRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			assertEqual(t, got, tt.want, "rule = %#v, want %#v", got, tt.want)
+		})
+	}
+}
+
+func TestTabloid_Colorize(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "web-2", "web-3"}},
+		{Title: "READY", ExprTitle: "ready", Values: []string{"1/1", "0/1", "1/1"}},
+		{Title: "STATUS", ExprTitle: "status", Values: []string{"Running", "Error", "Running"}},
+	}
+
+	rules := []ColorRule{
+		{Expression: `status == "Running"`, Style: "\033[32m"},
+		{Expression: `isnotready(ready)`, Style: "\033[31m"},
+		{Expression: `name == "web-3"`, Style: "\033[1m"},
+		{Expression: `name == "web-3" && status == "Running"`, Style: "\033[4m"},
+	}
+
+	got, err := New(nil).Colorize(columns, rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := [][]string{
+		{"web-1", "web-2", "\033[1m\033[4mweb-3\033[0m"},
+		{"1/1", "\033[31m0/1\033[0m", "1/1"},
+		{"\033[32mRunning\033[0m", "Error", "\033[32m\033[4mRunning\033[0m"},
+	}
+
+	for i, c := range got {
+		assertEqual(t, c.Styled, want[i], "column %q styled values = %q, want %q", c.Title, c.Styled, want[i])
+		assertEqual(t, c.Values, columns[i].Values, "column %q values must not change", c.Title)
+	}
+}
+
+func TestTabloid_HighlightMatches(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "api-1"}},
+		{Title: "IMAGE", ExprTitle: "image", Values: []string{"nginx:1.25", "api:2"}},
+	}
+
+	got, err := New(nil).HighlightMatches(columns, `name =~ "^(web|api)" && image !~ "latest" && image =~ "\\d+"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := [][]string{
+		{matchStart + "web" + matchEnd + "-1", matchStart + "api" + matchEnd + "-1"},
+		{"nginx:" + matchStart + "1" + matchEnd + "." + matchStart + "25" + matchEnd, "api:" + matchStart + "2" + matchEnd},
+	}
+
+	for i, c := range got {
+		assertEqual(t, c.Styled, want[i], "column %q styled values = %q, want %q", c.Title, c.Styled, want[i])
+	}
+}
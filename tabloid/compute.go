@@ -0,0 +1,94 @@
+package tabloid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+)
+
+// AddColumn appends a new column to the dataset whose values are the result
+// of evaluating the given expression against every row. The expression has
+// access to the same functions as Filter, and the resulting column can be
+// used by later expressions, selections and outputs like any other column.
+// If the expression returns a number or a boolean for every row, the column
+// is a number or a bool column. Hidden columns with the same title are
+// replaced.
+func (t *Tabloid) AddColumn(columns []Column, title, expression string) ([]Column, error) {
+	title = strings.TrimSpace(title)
+	expression = strings.TrimSpace(expression)
+
+	if title == "" {
+		return nil, fmt.Errorf("computed columns must have a title")
+	}
+
+	if expression == "" {
+		return nil, fmt.Errorf("computed column %q must have an expression", title)
+	}
+
+	exprTitle := fnKey(title)
+	columns = withoutHidden(columns, title, exprTitle)
+	if hasColumn(columns, title, exprTitle) {
+		return nil, &DuplicateColumnTitleError{Title: title}
+	}
+
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(expression, funcs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process expression %q for column %q: %w", expression, title, err)
+	}
+
+	column := Column{
+		VisualPosition: len(columns) + 1,
+		Title:          title,
+		ExprTitle:      exprTitle,
+		Values:         make([]string, 0, rowCount(columns)),
+	}
+
+	numeric, boolean := rowCount(columns) > 0, rowCount(columns) > 0
+	for pos := 0; pos < rowCount(columns); pos++ {
+		result, err := expr.Evaluate(rowValues(columns, pos))
+		if err != nil {
+			return nil, fmt.Errorf("unable to evaluate expression for column %q in row %d: %w", title, pos+1, err)
+		}
+
+		if _, ok := result.(float64); !ok {
+			numeric = false
+		}
+
+		if _, ok := result.(bool); !ok {
+			boolean = false
+		}
+
+		column.Values = append(column.Values, formatComputed(result))
+	}
+
+	// Columns computed as numbers or booleans keep their type in later
+	// expressions.
+	switch {
+	case numeric:
+		column.Type = TypeNumber
+	case boolean:
+		column.Type = TypeBool
+	}
+
+	t.logger.Printf("added computed column %q with expression %q", title, expression)
+	return append(columns, column), nil
+}
+
+// formatComputed converts the result of an expression back into the string
+// form used by column values.
+func formatComputed(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
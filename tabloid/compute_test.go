@@ -0,0 +1,101 @@
+package tabloid
+
+import "testing"
+
+func TestTabloid_AddColumn(t *testing.T) {
+	columns := []Column{
+		{Title: "NAMESPACE", ExprTitle: "namespace", Values: []string{"default", "kube-system"}},
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web", "dns"}},
+		{Title: "RESTARTS", ExprTitle: "restarts", Values: []string{"0", "3 (2d ago)"}},
+		{Title: "RESTART COUNT", ExprTitle: "restart_count", Values: []string{"0", "3"}, Hidden: true},
+	}
+
+	tests := []struct {
+		name       string
+		title      string
+		expression string
+		want       []string
+		wantType   ColumnType
+		wantErr    bool
+	}{
+		{
+			name:       "string concatenation",
+			title:      "pod",
+			expression: `namespace + "/" + name`,
+			want:       []string{"default/web", "kube-system/dns"},
+		},
+		{
+			name:       "numeric function",
+			title:      "restart_count",
+			expression: `restartcount(restarts)`,
+			want:       []string{"0", "3"},
+			wantType:   TypeNumber,
+		},
+		{
+			name:       "boolean function",
+			title:      "restarted",
+			expression: `hasrestarts(restarts)`,
+			want:       []string{"false", "true"},
+			wantType:   TypeBool,
+		},
+		{
+			name:       "mixed results",
+			title:      "mixed",
+			expression: `restarts == "0" ? true : name`,
+			want:       []string{"true", "dns"},
+		},
+		{
+			name:       "duplicate title",
+			title:      "Name",
+			expression: `name`,
+			wantErr:    true,
+		},
+		{
+			name:       "invalid expression",
+			title:      "broken",
+			expression: `name ==`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(nil).AddColumn(columns, tt.title, tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			added := got[len(got)-1]
+			assertEqual(t, added.Title, tt.title, "title = %q, want %q", added.Title, tt.title)
+			assertEqual(t, added.Values, tt.want, "values = %q, want %q", added.Values, tt.want)
+			assertEqual(t, added.Type, tt.wantType, "type = %q, want %q", added.Type, tt.wantType)
+		})
+	}
+}
+
+func TestTabloid_AddColumn_filterBool(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web", "db", "cache"}},
+		{Title: "AGE", ExprTitle: "age", Values: []string{"2d", "45d", "31d"}},
+	}
+
+	tab := New(nil)
+	computed, err := tab.AddColumn(columns, "stale", `olderthan(age, "30d")`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, expression := range []string{"stale == true", "stale", "!stale == false"} {
+		got, err := tab.Filter(computed, expression)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", expression, err)
+		}
+
+		want := []string{"db", "cache"}
+		assertEqual(t, got[0].Values, want, "%s: names = %q, want %q", expression, got[0].Values, want)
+	}
+}
@@ -0,0 +1,105 @@
+package tabloid
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// isCSV reports whether the input is read as comma or tab-separated values.
+func (t *Tabloid) isCSV() bool {
+	return t.format == InputCSV || t.format == InputTSV
+}
+
+// newCSVReader creates a reader for the records of the input format in use.
+// Records are allowed to have any amount of fields, so short ones can be
+// padded instead of failing.
+func (t *Tabloid) newCSVReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	if t.format == InputTSV {
+		reader.Comma = '\t'
+		reader.LazyQuotes = true
+	}
+
+	return reader
+}
+
+// csvTitles creates the columns for the titles of the first record.
+func csvTitles(record []string) ([]Column, error) {
+	titles := make([]string, 0, len(record))
+	for _, title := range record {
+		titles = append(titles, strings.TrimSpace(title))
+	}
+
+	return newColumns(titles)
+}
+
+// parseCSVLine parses a single line as a record, falling back to splitting
+// it on the separator when it isn't a valid or complete record, like a
+// quoted value spanning several lines.
+func (t *Tabloid) parseCSVLine(line string) []string {
+	reader := t.newCSVReader(strings.NewReader(line))
+
+	record, err := reader.Read()
+	if err != nil {
+		t.logger.Printf("unable to parse line as a record, splitting it instead: %s", err)
+		return strings.Split(line, string(reader.Comma))
+	}
+
+	return record
+}
+
+// parseCSV reads the whole input as records, using the first one as titles,
+// after removing any escape sequences.
+// Records shorter than the titles get empty values, while longer ones are an
+// error, since their values can't be assigned to a column.
+func (t *Tabloid) parseCSV() ([]Column, error) {
+	reader := t.newCSVReader(strings.NewReader(StripANSI(t.input.String())))
+
+	var columns []Column
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error while reading %s input: %w", t.format, err)
+		}
+
+		if columns == nil {
+			columns, err = csvTitles(record)
+			if err != nil {
+				return nil, err
+			}
+
+			t.logger.Printf("finished parsing columns, found: %d", len(columns))
+			continue
+		}
+
+		if len(record) > len(columns) {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("record in line %d has %d values, but there are only %d columns", line, len(record), len(columns))
+		}
+
+		for pos := range columns {
+			value := ""
+			if pos < len(record) {
+				value = record[pos]
+			}
+
+			columns[pos].Values = append(columns[pos].Values, value)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no data found in input")
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,95 @@
+package tabloid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTabloid_ParseColumns_csv(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  InputFormat
+		input   string
+		want    map[string][]string
+		wantErr bool
+	}{
+		{
+			name:   "comma-separated values",
+			format: InputCSV,
+			input:  "Name,Full Name,Age\nweb,\"Web, the server\",3\napi,\"two\nlines\",\n",
+			want: map[string][]string{
+				"name":      {"web", "api"},
+				"full_name": {"Web, the server", "two\nlines"},
+				"age":       {"3", ""},
+			},
+		},
+		{
+			name:   "tab-separated values with quotes",
+			format: InputTSV,
+			input:  "name\tcomment\nweb\tsays \"hi\"\n",
+			want: map[string][]string{
+				"name":    {"web"},
+				"comment": {"says \"hi\""},
+			},
+		},
+		{
+			name:   "short records are padded",
+			format: InputCSV,
+			input:  "a,b,c\n1\n",
+			want: map[string][]string{
+				"a": {"1"},
+				"b": {""},
+				"c": {""},
+			},
+		},
+		{
+			name:    "long records are an error",
+			format:  InputCSV,
+			input:   "a,b\n1,2,3\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate titles",
+			format:  InputCSV,
+			input:   "a,a\n1,2\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := New(bytes.NewBufferString(tt.input))
+			tab.SetInputFormat(tt.format)
+
+			got, err := tab.ParseColumns()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			byTitle := make(map[string][]string, len(got))
+			for _, c := range got {
+				byTitle[c.ExprTitle] = c.Values
+			}
+
+			for title, want := range tt.want {
+				assertEqual(t, byTitle[title], want, "column %q values = %q, want %q", title, byTitle[title], want)
+			}
+		})
+	}
+}
+
+func TestTabloid_ParseRow_csv(t *testing.T) {
+	tab := New(nil)
+	tab.SetInputFormat(InputCSV)
+
+	heading, err := tab.ParseHeading(`NAME,"STATUS, LONG",AGE`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got := tab.ParseRow(heading, `web-1,"Running, ready"`)
+
+	want := []string{"web-1", "Running, ready", ""}
+	for i, c := range got {
+		assertEqual(t, c.Values, []string{want[i]}, "column %q values = %q, want %q", c.Title, c.Values, want[i])
+	}
+}
@@ -0,0 +1,109 @@
+package tabloid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DistinctKeep defines which of the rows sharing the same key is kept when
+// removing duplicates.
+type DistinctKeep string
+
+const (
+	// DistinctFirst keeps the first row of every key, where it was found.
+	DistinctFirst DistinctKeep = "first"
+
+	// DistinctLast keeps the last row of every key, where it was found.
+	DistinctLast DistinctKeep = "last"
+)
+
+// ParseDistinctKeep converts a string into a DistinctKeep, failing if it's
+// not one of the known values.
+func ParseDistinctKeep(s string) (DistinctKeep, error) {
+	switch k := DistinctKeep(strings.ToLower(strings.TrimSpace(s))); k {
+	case DistinctFirst, DistinctLast:
+		return k, nil
+	}
+
+	return "", fmt.Errorf("unknown value %q: must be one of %q or %q", s, DistinctFirst, DistinctLast)
+}
+
+// Distinct removes the rows whose key was already found in another row,
+// keeping the first or the last one of every key. Keys are the values of
+// the key columns in the same row, which must have as many rows as the
+// columns; when there are no key columns, the whole row is the key. If a
+// count title is given, a number column with that title is added, with
+// the amount of rows every kept row stands for.
+func (t *Tabloid) Distinct(columns, keys []Column, keep DistinctKeep, countTitle string) ([]Column, error) {
+	if keep != DistinctFirst && keep != DistinctLast {
+		return nil, fmt.Errorf("unknown value %q to keep distinct rows: must be one of %q or %q", keep, DistinctFirst, DistinctLast)
+	}
+
+	if len(keys) == 0 {
+		keys = columns
+	}
+
+	if rowCount(keys) != rowCount(columns) {
+		return nil, fmt.Errorf("key columns have %d rows, but the columns have %d", rowCount(keys), rowCount(columns))
+	}
+
+	if countTitle != "" && hasColumn(columns, countTitle, fnKey(countTitle)) {
+		return nil, &DuplicateColumnTitleError{Title: countTitle}
+	}
+
+	var (
+		rowKeys = make([]string, rowCount(columns))
+		kept    = make(map[string]int)
+		counts  = make(map[string]int)
+	)
+
+	for row := range rowKeys {
+		key := rowKey(keys, row)
+		rowKeys[row] = key
+		counts[key]++
+
+		if _, found := kept[key]; !found || keep == DistinctLast {
+			kept[key] = row
+		}
+	}
+
+	// Kept rows stay where they were found, so keeping the last ones can
+	// change the order of the keys.
+	positions := make([]int, 0, len(kept))
+	for row, key := range rowKeys {
+		if kept[key] == row {
+			positions = append(positions, row)
+		}
+	}
+
+	distinct := pickRows(columns, positions)
+	if countTitle == "" {
+		return distinct, nil
+	}
+
+	count := Column{
+		VisualPosition: len(distinct) + 1,
+		Title:          countTitle,
+		ExprTitle:      fnKey(countTitle),
+		Type:           TypeNumber,
+		Values:         make([]string, 0, len(positions)),
+	}
+
+	for _, pos := range positions {
+		count.Values = append(count.Values, strconv.Itoa(counts[rowKeys[pos]]))
+	}
+
+	return append(distinct, count), nil
+}
+
+// rowKey joins the values of a row, so rows with the same values have the
+// same key.
+func rowKey(columns []Column, row int) string {
+	values := make([]string, 0, len(columns))
+	for _, c := range columns {
+		values = append(values, c.Values[row])
+	}
+
+	return strings.Join(values, "\x00")
+}
@@ -0,0 +1,101 @@
+package tabloid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTabloid_Distinct(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "db", "web-2", "web-1", "cache"}},
+		{Title: "IMAGE", ExprTitle: "image", Values: []string{"nginx", "postgres", "nginx", "nginx", "redis"}},
+	}
+
+	tests := []struct {
+		name       string
+		keys       []Column
+		keep       DistinctKeep
+		countTitle string
+		wantNames  []string
+		wantCounts []string
+	}{
+		{
+			name:      "whole rows",
+			keep:      DistinctFirst,
+			wantNames: []string{"web-1", "db", "web-2", "cache"},
+		},
+		{
+			name:       "first row per key",
+			keys:       columns[1:],
+			keep:       DistinctFirst,
+			countTitle: "COUNT",
+			wantNames:  []string{"web-1", "db", "cache"},
+			wantCounts: []string{"3", "1", "1"},
+		},
+		{
+			name:       "last row per key",
+			keys:       columns[1:],
+			keep:       DistinctLast,
+			countTitle: "COUNT",
+			wantNames:  []string{"db", "web-1", "cache"},
+			wantCounts: []string{"1", "3", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(nil).Distinct(columns, tt.keys, tt.keep, tt.countTitle)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertEqual(t, got[0].Values, tt.wantNames, "names = %q, want %q", got[0].Values, tt.wantNames)
+
+			if tt.countTitle == "" {
+				if len(got) != len(columns) {
+					t.Errorf("got %d columns, want %d", len(got), len(columns))
+				}
+				return
+			}
+
+			count := got[len(got)-1]
+			if count.Title != tt.countTitle || count.Type != TypeNumber {
+				t.Errorf("count column = %q of type %q, want %q of type %q", count.Title, count.Type, tt.countTitle, TypeNumber)
+			}
+
+			assertEqual(t, count.Values, tt.wantCounts, "counts = %q, want %q", count.Values, tt.wantCounts)
+		})
+	}
+}
+
+func TestTabloid_Distinct_errors(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web", "web"}},
+	}
+
+	var dupErr *DuplicateColumnTitleError
+	if _, err := New(nil).Distinct(columns, nil, DistinctFirst, "name"); !errors.As(err, &dupErr) {
+		t.Errorf("error = %v, want a duplicate column title error", err)
+	}
+
+	keys := []Column{{Title: "IMAGE", ExprTitle: "image", Values: []string{"nginx"}}}
+	if _, err := New(nil).Distinct(columns, keys, DistinctFirst, ""); err == nil {
+		t.Error("expected an error for key columns with a different amount of rows")
+	}
+
+	if _, err := New(nil).Distinct(columns, nil, "middle", ""); err == nil {
+		t.Error("expected an error for an unknown value to keep")
+	}
+}
+
+func TestParseDistinctKeep(t *testing.T) {
+	for input, want := range map[string]DistinctKeep{"first": DistinctFirst, " LAST ": DistinctLast} {
+		got, err := ParseDistinctKeep(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDistinctKeep(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := ParseDistinctKeep("any"); err == nil {
+		t.Error("expected an error for an unknown value")
+	}
+}
@@ -0,0 +1,286 @@
+package tabloid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+)
+
+// Explanation describes how a filter expression was evaluated for a single
+// row: its final result as well as the intermediate value of every
+// sub-expression and function call.
+type Explanation struct {
+	Row    int
+	Values map[string]interface{}
+	Result bool
+	Err    error
+	Steps  []ExplainStep
+}
+
+// ExplainStep is the result of evaluating a sub-expression or a function
+// call. Depth indicates how nested the step is within the original
+// expression, starting at zero.
+type ExplainStep struct {
+	Expression string
+	Value      interface{}
+	Err        error
+	Depth      int
+}
+
+// String returns the step in the form of "<expression> = <value>".
+func (s ExplainStep) String() string {
+	if s.Err != nil {
+		return fmt.Sprintf("%s = error: %s", s.Expression, s.Err)
+	}
+
+	return fmt.Sprintf("%s = %s", s.Expression, formatValue(s.Value))
+}
+
+// explainNode is a sub-expression split at the top-level logical operators
+// of its parent, along with its own compiled expression.
+type explainNode struct {
+	text     string
+	expr     *govaluate.EvaluableExpression
+	children []*explainNode
+}
+
+// callRecorder wraps the expression functions so every call made while
+// evaluating a sub-expression, along with its arguments and result, can be
+// reported back.
+type callRecorder struct {
+	funcs map[string]govaluate.ExpressionFunction
+	calls []ExplainStep
+}
+
+func newCallRecorder() *callRecorder {
+	rec := &callRecorder{funcs: make(map[string]govaluate.ExpressionFunction, len(funcs))}
+
+	for name, fn := range funcs {
+		name, fn := name, fn
+		rec.funcs[name] = func(args ...interface{}) (interface{}, error) {
+			result, err := fn(args...)
+
+			formatted := make([]string, 0, len(args))
+			for _, arg := range args {
+				formatted = append(formatted, formatValue(arg))
+			}
+
+			rec.calls = append(rec.calls, ExplainStep{
+				Expression: fmt.Sprintf("%s(%s)", name, strings.Join(formatted, ", ")),
+				Value:      result,
+				Err:        err,
+			})
+			return result, err
+		}
+	}
+
+	return rec
+}
+
+// Explain evaluates the expression against every row in the columns and
+// reports, for each one of them, whether it would've been kept by Filter
+// and why.
+func (t *Tabloid) Explain(columns []Column, expression string) ([]Explanation, error) {
+	expression = strings.TrimSpace(expression)
+
+	if expression == "" {
+		return nil, fmt.Errorf("an expression is required to explain its results")
+	}
+
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(expression, funcs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process expression %q: %w", expression, err)
+	}
+
+	rec := newCallRecorder()
+
+	root, err := buildExplainTree(expression, rec)
+	if err != nil {
+		return nil, err
+	}
+
+	var explanations []Explanation
+	for pos := 0; pos < rowCount(columns); pos++ {
+		row := rowValues(columns, pos)
+		explanation := Explanation{Row: pos + 1, Values: row}
+
+		result, err := expr.Evaluate(row)
+		if err == nil {
+			chosen, ok := result.(bool)
+			if !ok {
+				return nil, fmt.Errorf("expression %q must return a boolean value", expression)
+			}
+			explanation.Result = chosen
+		}
+		explanation.Err = err
+
+		explanation.Steps = explainSteps(root, row, rec, -1)
+		explanations = append(explanations, explanation)
+	}
+
+	return explanations, nil
+}
+
+// buildExplainTree splits the expression at its top-level "&&" and "||"
+// operators, recursively, compiling each sub-expression on its own.
+func buildExplainTree(expression string, rec *callRecorder) (*explainNode, error) {
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(expression, rec.funcs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process sub-expression %q: %w", expression, err)
+	}
+
+	node := &explainNode{text: expression, expr: expr}
+
+	clauses := splitClauses(unwrapParens(expression))
+	if len(clauses) < 2 {
+		return node, nil
+	}
+
+	for _, clause := range clauses {
+		child, err := buildExplainTree(clause, rec)
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, child)
+	}
+
+	return node, nil
+}
+
+// explainSteps evaluates every sub-expression in the tree for the given row.
+// Function calls are only reported for the innermost sub-expressions, since
+// their parents would repeat them. The root of the tree is skipped, since
+// its value is the row's result.
+func explainSteps(node *explainNode, row map[string]interface{}, rec *callRecorder, depth int) []ExplainStep {
+	if len(node.children) > 0 {
+		var steps []ExplainStep
+
+		childDepth := depth + 1
+		if depth < 0 {
+			childDepth = 0
+		} else {
+			steps = append(steps, evaluateStep(node, row, depth))
+		}
+
+		for _, child := range node.children {
+			steps = append(steps, explainSteps(child, row, rec, childDepth)...)
+		}
+
+		return steps
+	}
+
+	if depth < 0 {
+		depth = 0
+	}
+
+	rec.calls = nil
+	step := evaluateStep(node, row, depth)
+	calls := rec.calls
+
+	steps := []ExplainStep{step}
+	for _, call := range calls {
+		call.Depth = depth + 1
+		steps = append(steps, call)
+	}
+
+	return steps
+}
+
+func evaluateStep(node *explainNode, row map[string]interface{}, depth int) ExplainStep {
+	value, err := node.expr.Evaluate(row)
+	return ExplainStep{Expression: node.text, Value: value, Err: err, Depth: depth}
+}
+
+// splitClauses splits an expression at its top-level "&&" and "||"
+// operators, ignoring those found inside parenthesis or quoted strings.
+func splitClauses(expression string) []string {
+	var (
+		clauses []string
+		depth   int
+		quote   byte
+		start   int
+	)
+
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case depth == 0 && i+1 < len(expression) && (expression[i:i+2] == "&&" || expression[i:i+2] == "||"):
+			clauses = append(clauses, strings.TrimSpace(expression[start:i]))
+			start = i + 2
+			i++
+		}
+	}
+
+	return append(clauses, strings.TrimSpace(expression[start:]))
+}
+
+// unwrapParens removes the parenthesis surrounding the whole expression, if
+// any, so its contents can be split further. Parenthesis inside quoted
+// strings are ignored.
+func unwrapParens(expression string) string {
+	for strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		var (
+			depth       int
+			quote       byte
+			closesAtEnd = true
+		)
+
+		for i := 0; i < len(expression); i++ {
+			c := expression[i]
+
+			switch {
+			case quote != 0:
+				if c == '\\' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+			}
+
+			if depth == 0 && i != len(expression)-1 {
+				closesAtEnd = false
+				break
+			}
+		}
+
+		if !closesAtEnd {
+			break
+		}
+
+		expression = strings.TrimSpace(expression[1 : len(expression)-1])
+	}
+
+	return expression
+}
+
+// formatValue prints strings quoted and any other value as-is, mimicking how
+// they would be written in an expression.
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,126 @@
+package tabloid
+
+import "testing"
+
+func Test_splitClauses(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       []string
+	}{
+		{
+			name:       "single clause",
+			expression: `isready(ready)`,
+			want:       []string{`isready(ready)`},
+		},
+		{
+			name:       "top-level operators",
+			expression: `isready(ready) && name =~ "^frontend" || age == "1d"`,
+			want:       []string{`isready(ready)`, `name =~ "^frontend"`, `age == "1d"`},
+		},
+		{
+			name:       "operators within parenthesis and strings",
+			expression: `(a == "1" || b == "2") && c == "x && y"`,
+			want:       []string{`(a == "1" || b == "2")`, `c == "x && y"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitClauses(tt.expression)
+			assertEqual(t, got, tt.want, "splitClauses() = %q, want %q", got, tt.want)
+		})
+	}
+}
+
+func Test_unwrapParens(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{expression: `(a || b)`, want: `a || b`},
+		{expression: `((a || b))`, want: `a || b`},
+		{expression: `(a || b) && (c || d)`, want: `(a || b) && (c || d)`},
+		{expression: `a`, want: `a`},
+		{expression: `(a == "(") || (b == ")")`, want: `(a == "(") || (b == ")")`},
+		{expression: `("a(" == x)`, want: `"a(" == x`},
+		{expression: `(x == "\")" || y == ')')`, want: `x == "\")" || y == ')'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got := unwrapParens(tt.expression)
+			assertEqual(t, got, tt.want, "unwrapParens() = %q, want %q", got, tt.want)
+		})
+	}
+}
+
+func TestTabloid_Explain(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "web-2"}},
+		{Title: "READY", ExprTitle: "ready", Values: []string{"1/1", "0/1"}},
+		{Title: "STATUS", ExprTitle: "status", Values: []string{"Running", "Error"}},
+	}
+
+	explanations, err := New(nil).Explain(columns, `(isready(ready) || status == "Running") && name != "db"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(explanations) != 2 {
+		t.Fatalf("got %d explanations, want 2", len(explanations))
+	}
+
+	tests := []struct {
+		result bool
+		steps  []string
+	}{
+		{
+			result: true,
+			steps: []string{
+				`(isready(ready) || status == "Running") = true`,
+				`isready(ready) = true`,
+				`isready("1/1") = true`,
+				`status == "Running" = true`,
+				`name != "db" = true`,
+			},
+		},
+		{
+			result: false,
+			steps: []string{
+				`(isready(ready) || status == "Running") = false`,
+				`isready(ready) = false`,
+				`isready("0/1") = false`,
+				`status == "Running" = false`,
+				`name != "db" = true`,
+			},
+		},
+	}
+	for i, tt := range tests {
+		e := explanations[i]
+		if e.Row != i+1 || e.Result != tt.result || e.Err != nil {
+			t.Errorf("row %d = %d, %v, %v, want %d, %v", i+1, e.Row, e.Result, e.Err, i+1, tt.result)
+		}
+
+		steps := make([]string, 0, len(e.Steps))
+		for _, s := range e.Steps {
+			steps = append(steps, s.String())
+		}
+		assertEqual(t, steps, tt.steps, "row %d steps = %q, want %q", i+1, steps, tt.steps)
+
+		wantDepths := []int{0, 1, 2, 1, 0}
+		for j, s := range e.Steps {
+			if j < len(wantDepths) && s.Depth != wantDepths[j] {
+				t.Errorf("row %d step %q has depth %d, want %d", i+1, s.Expression, s.Depth, wantDepths[j])
+			}
+		}
+	}
+}
+
+func TestTabloid_Explain_errors(t *testing.T) {
+	columns := []Column{{Title: "NAME", ExprTitle: "name", Values: []string{"web-1"}}}
+
+	for _, expression := range []string{"", "name ==", `name + "-1"`} {
+		if _, err := New(nil).Explain(columns, expression); err == nil {
+			t.Errorf("expected an error explaining %q", expression)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package tabloid
 
 import (
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -53,6 +55,68 @@ func hasrestarts(args ...interface{}) (interface{}, error) {
 	return reRestart.MatchString(str), nil
 }
 
+// restartcount returns the amount of restarts from a string in the form of
+// <number> or <number> (<duration> ago), as a number.
+func restartcount(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("restartcount function only accepts one argument")
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("restartcount function only accepts string arguments")
+	}
+
+	count, _, _ := strings.Cut(strings.TrimSpace(str), " ")
+	n, err := strconv.ParseFloat(count, 64)
+	if err != nil {
+		return nil, fmt.Errorf("restartcount function only accepts string arguments in the form of <number> or <number> (<duration> ago)")
+	}
+
+	return n, nil
+}
+
+var reByteSize = regexp.MustCompile(`^([0-9]*\.?[0-9]+)\s*(?:([kKMGTPE])(i?)(B?)|B?)$`)
+
+// byteSizeUnits are the powers of each size unit.
+var byteSizeUnits = map[string]float64{"k": 1, "K": 1, "M": 2, "G": 3, "T": 4, "P": 5, "E": 6}
+
+// bytesize converts a human-readable size, like "1.2GB", "59G" or "512Mi",
+// into an amount of bytes. Units followed by "B", like the ones docker
+// uses, are powers of 1000, while units on their own, like the ones df
+// uses, or followed by "i", like the ones kubectl uses, are powers of 1024.
+func bytesize(args ...interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("bytesize function only accepts one argument")
+	}
+
+	if n, ok := args[0].(float64); ok {
+		return n, nil
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("bytesize function only accepts string arguments")
+	}
+
+	m := reByteSize.FindStringSubmatch(strings.TrimSpace(str))
+	if m == nil {
+		return nil, fmt.Errorf("bytesize function only accepts sizes in the form of <number><unit>, like 1.2GB, 59G or 512Mi, got %q", str)
+	}
+
+	n, _ := strconv.ParseFloat(m[1], 64)
+	if m[2] == "" {
+		return n, nil
+	}
+
+	base := 1024.0
+	if m[3] == "" && m[4] == "B" {
+		base = 1000
+	}
+
+	return n * math.Pow(base, byteSizeUnits[m[2]]), nil
+}
+
 // parseDurations parses two string arguments into time.Duration values.
 func parseDurations(args ...interface{}) (time.Duration, time.Duration, error) {
 	if len(args) != 2 {
@@ -131,9 +195,11 @@ var funcs = map[string]govaluate.ExpressionFunction{
 		return !restarts.(bool), err
 	},
 
-	"olderthan":   olderThan,
-	"olderthaneq": olderThanEq,
-	"newerthan":   newerThan,
-	"newerthaneq": newerThanEq,
-	"eqduration":  eqduration,
+	"restartcount": restartcount,
+	"olderthan":    olderThan,
+	"olderthaneq":  olderThanEq,
+	"newerthan":    newerThan,
+	"newerthaneq":  newerThanEq,
+	"eqduration":   eqduration,
+	"bytesize":     bytesize,
 }
@@ -359,3 +359,108 @@ func TestDurations(t *testing.T) {
 		})
 	}
 }
+
+func Test_restartcount(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "no restarts",
+			args: []interface{}{"0"},
+			want: float64(0),
+		},
+		{
+			name: "restarts with time",
+			args: []interface{}{"592 (3m33s ago)"},
+			want: float64(592),
+		},
+		{
+			name:    "not a number",
+			args:    []interface{}{"Running"},
+			wantErr: true,
+		},
+		{
+			name:    "not a string",
+			args:    []interface{}{1},
+			wantErr: true,
+		},
+		{
+			name:    "more than 1 argument",
+			args:    []interface{}{"1", "2"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := restartcount(tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("restartcount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assertEqual(t, got, tt.want, "restartcount() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func Test_bytesize(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "plain bytes",
+			args: []interface{}{"512B"},
+			want: float64(512),
+		},
+		{
+			name: "decimal units",
+			args: []interface{}{"1.2GB"},
+			want: float64(1.2e9),
+		},
+		{
+			name: "lowercase kilobytes",
+			args: []interface{}{"3.5kB"},
+			want: float64(3500),
+		},
+		{
+			name: "binary units without suffix",
+			args: []interface{}{"59G"},
+			want: float64(59 * 1024 * 1024 * 1024),
+		},
+		{
+			name: "binary units with suffix",
+			args: []interface{}{"512Mi"},
+			want: float64(512 * 1024 * 1024),
+		},
+		{
+			name: "numbers are returned as they are",
+			args: []interface{}{float64(42)},
+			want: float64(42),
+		},
+		{
+			name:    "not a size",
+			args:    []interface{}{"big"},
+			wantErr: true,
+		},
+		{
+			name:    "more than 1 argument",
+			args:    []interface{}{"1G", "2G"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bytesize(tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bytesize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assertEqual(t, got, tt.want, "bytesize() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package tabloid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ExtractPolicy defines what to do with rows whose value doesn't match the
+// extraction regular expression.
+type ExtractPolicy string
+
+const (
+	// ExtractEmpty keeps the row, leaving the extracted columns empty.
+	ExtractEmpty ExtractPolicy = "empty"
+
+	// ExtractDrop removes the row from the dataset.
+	ExtractDrop ExtractPolicy = "drop"
+
+	// ExtractError fails the extraction altogether.
+	ExtractError ExtractPolicy = "error"
+)
+
+// ParseExtractPolicy converts a string into an ExtractPolicy, failing if it's
+// not one of the known policies.
+func ParseExtractPolicy(s string) (ExtractPolicy, error) {
+	switch p := ExtractPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case ExtractEmpty, ExtractDrop, ExtractError:
+		return p, nil
+	}
+
+	return "", fmt.Errorf("unknown policy %q: must be one of %q, %q or %q", s, ExtractEmpty, ExtractDrop, ExtractError)
+}
+
+// Extract matches the values of the given column against a regular
+// expression and adds a new column per named capture group, containing the
+// captured text. Rows whose value doesn't match are handled according to
+// the given policy.
+func (t *Tabloid) Extract(columns []Column, source, pattern string, policy ExtractPolicy) ([]Column, error) {
+	pos, found := findColumn(columns, source)
+	if !found {
+		return nil, fmt.Errorf("column %q does not exist in the input dataset", source)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile regular expression %q: %w", pattern, err)
+	}
+
+	var (
+		groups  []int
+		created []Column
+	)
+
+	for idx, name := range re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+
+		exprTitle := fnKey(name)
+		if hasColumn(columns, name, exprTitle) || hasColumn(created, name, exprTitle) {
+			return nil, &DuplicateColumnTitleError{Title: name}
+		}
+
+		groups = append(groups, idx)
+		created = append(created, Column{
+			VisualPosition: len(columns) + len(created) + 1,
+			Title:          name,
+			ExprTitle:      exprTitle,
+			Values:         make([]string, 0, rowCount(columns)),
+		})
+	}
+
+	if len(created) == 0 {
+		return nil, fmt.Errorf("regular expression %q has no named capture groups, use (?P<name>...) to define them", pattern)
+	}
+
+	kept := make([]int, 0, rowCount(columns))
+	for row, value := range columns[pos].Values {
+		matches := re.FindStringSubmatch(value)
+
+		if matches == nil {
+			switch policy {
+			case ExtractDrop:
+				t.logger.Printf("dropping row %d: value %q does not match %q", row+1, value, pattern)
+				continue
+			case ExtractError:
+				return nil, fmt.Errorf("value %q in row %d of column %q does not match %q", value, row+1, columns[pos].Title, pattern)
+			}
+		}
+
+		for i, group := range groups {
+			var captured string
+			if matches != nil {
+				captured = matches[group]
+			}
+			created[i].Values = append(created[i].Values, captured)
+		}
+
+		kept = append(kept, row)
+	}
+
+	return append(pickRows(columns, kept), created...), nil
+}
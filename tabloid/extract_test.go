@@ -0,0 +1,100 @@
+package tabloid
+
+import "testing"
+
+func TestTabloid_Extract(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"frontend-5c6c94684f-5kzbk", "etcd-0"}},
+		{Title: "IMAGE", ExprTitle: "image", Values: []string{"nginx:1.25", "etcd:3.5"}},
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		pattern string
+		policy  ExtractPolicy
+		want    []Column
+		wantErr bool
+	}{
+		{
+			name:    "empty on mismatch",
+			source:  "name",
+			pattern: `^(?P<app>.+)-[a-z0-9]{9,10}-[a-z0-9]{5}$`,
+			policy:  ExtractEmpty,
+			want: []Column{
+				{Title: "NAME", Values: []string{"frontend-5c6c94684f-5kzbk", "etcd-0"}},
+				{Title: "IMAGE", Values: []string{"nginx:1.25", "etcd:3.5"}},
+				{Title: "app", Values: []string{"frontend", ""}},
+			},
+		},
+		{
+			name:    "drop on mismatch",
+			source:  "NAME",
+			pattern: `^(?P<app>.+)-[a-z0-9]{9,10}-[a-z0-9]{5}$`,
+			policy:  ExtractDrop,
+			want: []Column{
+				{Title: "NAME", Values: []string{"frontend-5c6c94684f-5kzbk"}},
+				{Title: "IMAGE", Values: []string{"nginx:1.25"}},
+				{Title: "app", Values: []string{"frontend"}},
+			},
+		},
+		{
+			name:    "multiple groups",
+			source:  "image",
+			pattern: `^(?P<repository>[^:]+):(?P<tag>.+)$`,
+			policy:  ExtractError,
+			want: []Column{
+				{Title: "NAME", Values: []string{"frontend-5c6c94684f-5kzbk", "etcd-0"}},
+				{Title: "IMAGE", Values: []string{"nginx:1.25", "etcd:3.5"}},
+				{Title: "repository", Values: []string{"nginx", "etcd"}},
+				{Title: "tag", Values: []string{"1.25", "3.5"}},
+			},
+		},
+		{
+			name:    "error on mismatch",
+			source:  "name",
+			pattern: `^(?P<app>.+)-[a-z0-9]{9,10}-[a-z0-9]{5}$`,
+			policy:  ExtractError,
+			wantErr: true,
+		},
+		{
+			name:    "no named groups",
+			source:  "name",
+			pattern: `^(.+)-\d+$`,
+			policy:  ExtractEmpty,
+			wantErr: true,
+		},
+		{
+			name:    "unknown column",
+			source:  "status",
+			pattern: `(?P<s>.+)`,
+			policy:  ExtractEmpty,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate column",
+			source:  "name",
+			pattern: `(?P<image>.+)`,
+			policy:  ExtractEmpty,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(nil).Extract(columns, tt.source, tt.pattern, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("mismatched number of returned columns, got %v, want %v", got, tt.want)
+			}
+
+			for i, c := range got {
+				assertEqual(t, c.Title, tt.want[i].Title, "column %d title = %q, want %q", i+1, c.Title, tt.want[i].Title)
+				assertEqual(t, c.Values, tt.want[i].Values, "column %d values = %q, want %q", i+1, c.Values, tt.want[i].Values)
+			}
+		})
+	}
+}
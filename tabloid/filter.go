@@ -7,61 +7,112 @@ import (
 	"github.com/Knetic/govaluate"
 )
 
+// Filter returns the rows for which the expression evaluates to true. All
+// columns are always returned, even if no rows matched the expression.
 func (t *Tabloid) Filter(columns []Column, expression string) ([]Column, error) {
+	matched, _, err := t.Partition(columns, expression)
+	return matched, err
+}
+
+// Partition splits the rows in two groups: the ones for which the expression
+// evaluates to true, and the ones for which it evaluates to false. All
+// columns are always returned in both groups, even if they have no rows.
+func (t *Tabloid) Partition(columns []Column, expression string) ([]Column, []Column, error) {
 	expression = strings.TrimSpace(expression)
 
 	if expression == "" {
 		t.logger.Printf("no filter expression provided, returning all rows")
-		return columns, nil
+		return columns, pickRows(columns, nil), nil
 	}
 
-	expr, err := govaluate.NewEvaluableExpressionWithFunctions(expression, funcs)
+	results, err := t.Matches(columns, expression)
 	if err != nil {
-		return nil, fmt.Errorf("unable to process expression %q: %w", expression, err)
+		return nil, nil, err
 	}
 
-	newColumns := make([]Column, 0, len(columns))
-	for _, column := range columns {
-		for pos := range column.Values {
-			row := make(map[string]interface{})
-			for _, column := range columns {
-				row[column.ExprTitle] = column.Values[pos]
-			}
+	matched := make([]int, 0, rowCount(columns))
+	unmatched := make([]int, 0)
+	for pos, chosen := range results {
+		if chosen {
+			matched = append(matched, pos)
+		} else {
+			unmatched = append(unmatched, pos)
+		}
+	}
 
-			result, err := expr.Evaluate(row)
-			if err != nil {
-				t.logger.Printf("error type: %T", err)
-				return nil, fmt.Errorf("unable to evaluate expression for row %d: %w", pos+1, err)
-			}
+	t.logger.Printf("expression matched %d out of %d rows", len(matched), rowCount(columns))
+	return pickRows(columns, matched), pickRows(columns, unmatched), nil
+}
 
-			chosen, ok := result.(bool)
-			if !ok {
-				return nil, fmt.Errorf("expression %q must return a boolean value", expression)
-			}
+// Matches evaluates the expression against every row, returning whether
+// each one of them satisfies it, in the same order as the rows.
+func (t *Tabloid) Matches(columns []Column, expression string) ([]bool, error) {
+	expr, err := govaluate.NewEvaluableExpressionWithFunctions(strings.TrimSpace(expression), funcs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to process expression %q: %w", expression, err)
+	}
 
-			if chosen {
-				newColumns = upsertColumn(newColumns, column, column.Values[pos])
-			}
+	results := make([]bool, 0, rowCount(columns))
+	for pos := 0; pos < rowCount(columns); pos++ {
+		result, err := expr.Evaluate(rowValues(columns, pos))
+		if err != nil {
+			t.logger.Printf("error type: %T", err)
+			return nil, fmt.Errorf("unable to evaluate expression for row %d: %w", pos+1, err)
+		}
+
+		chosen, ok := result.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expression %q must return a boolean value", expression)
 		}
+
+		results = append(results, chosen)
+	}
+
+	return results, nil
+}
+
+// rowCount returns the amount of rows in the columns.
+func rowCount(columns []Column) int {
+	if len(columns) == 0 {
+		return 0
 	}
+	return len(columns[0].Values)
+}
 
-	return newColumns, nil
+// rowValues returns the values of the row at the given position, keyed by
+// their normalized column titles and converted to the column type, so they
+// can be used as expression parameters.
+func rowValues(columns []Column, pos int) map[string]interface{} {
+	row := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		row[column.ExprTitle] = typedValue(column.Type, column.Values[pos])
+	}
+	return row
 }
 
-func upsertColumn(columns []Column, column Column, data string) []Column {
-	for pos, v := range columns {
-		if v.ExprTitle == column.ExprTitle {
-			columns[pos].Values = append(columns[pos].Values, data)
-			return columns
+// pickRows returns a copy of the columns containing only the rows at the
+// given positions, in the order they were given.
+func pickRows(columns []Column, positions []int) []Column {
+	picked := make([]Column, 0, len(columns))
+
+	for _, column := range columns {
+		values := make([]string, 0, len(positions))
+		for _, pos := range positions {
+			values = append(values, column.Values[pos])
 		}
+
+		column.Values = values
+
+		if len(column.Styled) > 0 {
+			styled := make([]string, 0, len(positions))
+			for _, pos := range positions {
+				styled = append(styled, column.Styled[pos])
+			}
+			column.Styled = styled
+		}
+
+		picked = append(picked, column)
 	}
 
-	return append(columns, Column{
-		VisualPosition: column.VisualPosition,
-		ExprTitle:      column.ExprTitle,
-		Title:          column.Title,
-		StartIndex:     column.StartIndex,
-		EndIndex:       column.EndIndex,
-		Values:         []string{data},
-	})
+	return picked
 }
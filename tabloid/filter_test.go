@@ -0,0 +1,94 @@
+package tabloid
+
+import "testing"
+
+func TestTabloid_Filter(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"frontend", "redis", "backend"}},
+		{Title: "READY", ExprTitle: "ready", Values: []string{"1/1", "0/1", "1/1"}},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       []Column
+		wantErr    bool
+	}{
+		{
+			name: "no expression",
+			want: columns,
+		},
+		{
+			name:       "some rows matched",
+			expression: `isready(ready)`,
+			want: []Column{
+				{Title: "NAME", Values: []string{"frontend", "backend"}},
+				{Title: "READY", Values: []string{"1/1", "1/1"}},
+			},
+		},
+		{
+			name:       "no rows matched",
+			expression: `name == "database"`,
+			want: []Column{
+				{Title: "NAME", Values: []string{}},
+				{Title: "READY", Values: []string{}},
+			},
+		},
+		{
+			name:       "non-boolean expression",
+			expression: `name`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(nil).Filter(columns, tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("mismatched number of returned columns, got %v, want %v", got, tt.want)
+			}
+
+			for i, c := range got {
+				assertEqual(t, c.Title, tt.want[i].Title, "column %d title = %q, want %q", i+1, c.Title, tt.want[i].Title)
+				assertEqual(t, c.Values, tt.want[i].Values, "column %d values = %q, want %q", i+1, c.Values, tt.want[i].Values)
+			}
+		})
+	}
+}
+
+func TestTabloid_Partition(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"frontend", "redis", "backend"}},
+		{Title: "READY", ExprTitle: "ready", Values: []string{"1/1", "0/1", "1/1"}},
+	}
+
+	matched, unmatched, err := New(nil).Partition(columns, `isready(ready)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assertEqual(t, matched[0].Values, []string{"frontend", "backend"}, "matched values = %q", matched[0].Values)
+	assertEqual(t, unmatched[0].Values, []string{"redis"}, "unmatched values = %q", unmatched[0].Values)
+}
+
+func TestTabloid_Matches(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"frontend", "redis", "backend"}},
+		{Title: "READY", ExprTitle: "ready", Values: []string{"1/1", "0/1", "1/1"}},
+	}
+
+	got, err := New(nil).Matches(columns, `isready(ready)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assertEqual(t, got, []bool{true, false, true}, "matches = %v", got)
+
+	if _, err := New(nil).Matches(columns, `name`); err == nil {
+		t.Error("expected an error for an expression that doesn't return a boolean")
+	}
+}
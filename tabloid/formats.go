@@ -0,0 +1,70 @@
+package tabloid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InputFormat defines how the input is read into columns.
+type InputFormat string
+
+const (
+	// InputTable reads a table printed for humans, whose columns are found
+	// from its heading. It's the default format.
+	InputTable InputFormat = "table"
+
+	// InputCSV reads comma-separated values, with the first record as titles.
+	InputCSV InputFormat = "csv"
+
+	// InputTSV reads tab-separated values, with the first record as titles.
+	InputTSV InputFormat = "tsv"
+
+	// InputBox reads tables whose cells are separated by "|" or Unicode
+	// box-drawing characters, like the ones printed by psql or mysql, and
+	// Markdown tables. They're also detected when reading a table.
+	InputBox InputFormat = "box"
+
+	// InputJSON reads an array of objects, or one object per line, with a
+	// column per key. Nested keys are joined with dots.
+	InputJSON InputFormat = "json"
+)
+
+// inputFormats are all the supported input formats.
+var inputFormats = []InputFormat{InputTable, InputCSV, InputTSV, InputBox, InputJSON}
+
+// InputFormats returns the names of all the supported input formats.
+func InputFormats() []string {
+	names := make([]string, 0, len(inputFormats))
+	for _, f := range inputFormats {
+		names = append(names, string(f))
+	}
+
+	return names
+}
+
+// ParseInputFormat converts a string into an InputFormat, failing if it's
+// not one of the supported formats.
+func ParseInputFormat(s string) (InputFormat, error) {
+	f := InputFormat(strings.ToLower(strings.TrimSpace(s)))
+	if f == "" {
+		return InputTable, nil
+	}
+
+	for _, known := range inputFormats {
+		if f == known {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown input format %q, must be one of: %s", s, strings.Join(InputFormats(), ", "))
+}
+
+// SetInputFormat sets the format the input is read with.
+func (t *Tabloid) SetInputFormat(f InputFormat) {
+	t.format = f
+}
+
+// readsTable reports whether the input is read as a table, the default.
+func (t *Tabloid) readsTable() bool {
+	return t.format == "" || t.format == InputTable
+}
@@ -0,0 +1,230 @@
+package tabloid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonTable collects the columns of a JSON input, in the order their keys
+// are first found, along with the kind of values found in each of them.
+type jsonTable struct {
+	columns []Column
+	index   map[string]int
+	titles  map[string]string
+	numbers []bool
+	bools   []bool
+	rows    int
+}
+
+// jsonValue is a flattened value of an object, along with its kind.
+type jsonValue struct {
+	key   string
+	value string
+	kind  ColumnType
+}
+
+// parseJSON reads the input as an array of objects, or as a stream of
+// objects like NDJSON, and flattens them into columns. Keys missing from
+// some objects produce empty values, and columns whose values are all
+// numbers or all booleans are typed accordingly.
+func (t *Tabloid) parseJSON() ([]Column, error) {
+	table := &jsonTable{index: make(map[string]int), titles: make(map[string]string)}
+
+	dec := json.NewDecoder(t.input)
+	dec.UseNumber()
+
+	isArray := bytes.HasPrefix(bytes.TrimSpace(t.input.Bytes()), []byte("["))
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("error while reading json input: %w", err)
+		}
+	}
+
+	for n := 1; !isArray || dec.More(); n++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if !isArray && errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("error while reading json input: %w", err)
+		}
+
+		values, err := flattenJSON(raw, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if values == nil {
+			return nil, fmt.Errorf("element %d of the json input is not an object", n)
+		}
+
+		if err := table.add(values); err != nil {
+			return nil, fmt.Errorf("element %d of the json input: %w", n, err)
+		}
+	}
+
+	if len(table.columns) == 0 {
+		return nil, fmt.Errorf("no data found in input")
+	}
+
+	t.logger.Printf("finished parsing json input, found %d columns and %d rows", len(table.columns), table.rows)
+	return table.typedColumns(), nil
+}
+
+// add appends the values of an object as a new row, adding a column for
+// every key not found before. Since values can't be told apart once
+// flattened, it fails if the object has the same flattened key twice, like
+// "meta.app" and "app" nested in "meta", or if a new key would be used with
+// the same name as another one in expressions, like "app-name" and
+// "App Name".
+func (table *jsonTable) add(values []jsonValue) error {
+	seen := make(map[string]struct{}, len(values))
+
+	for _, v := range values {
+		if _, found := seen[v.key]; found {
+			return fmt.Errorf("key %q is found more than once after joining nested keys with dots", v.key)
+		}
+		seen[v.key] = struct{}{}
+
+		if _, found := table.index[v.key]; found {
+			continue
+		}
+
+		exprTitle := fnKey(v.key)
+		if other, found := table.titles[exprTitle]; found {
+			return fmt.Errorf("keys %q and %q would both be named %q in expressions", other, v.key, exprTitle)
+		}
+		table.titles[exprTitle] = v.key
+
+		table.index[v.key] = len(table.columns)
+		table.columns = append(table.columns, Column{
+			VisualPosition: len(table.columns) + 1,
+			Title:          v.key,
+			ExprTitle:      exprTitle,
+			Values:         make([]string, table.rows),
+		})
+		table.numbers = append(table.numbers, true)
+		table.bools = append(table.bools, true)
+	}
+
+	for pos := range table.columns {
+		table.columns[pos].Values = append(table.columns[pos].Values, "")
+	}
+
+	for _, v := range values {
+		pos := table.index[v.key]
+		table.columns[pos].Values[table.rows] = v.value
+
+		if v.kind == "" {
+			continue
+		}
+
+		table.numbers[pos] = table.numbers[pos] && v.kind == TypeNumber
+		table.bools[pos] = table.bools[pos] && v.kind == TypeBool
+	}
+
+	table.rows++
+	return nil
+}
+
+// typedColumns returns the columns, typed as numbers or booleans when all
+// their values are. Columns without any value are strings.
+func (table *jsonTable) typedColumns() []Column {
+	for pos := range table.columns {
+		empty := true
+		for _, v := range table.columns[pos].Values {
+			if v != "" {
+				empty = false
+				break
+			}
+		}
+
+		switch {
+		case empty:
+		case table.numbers[pos]:
+			table.columns[pos].Type = TypeNumber
+		case table.bools[pos]:
+			table.columns[pos].Type = TypeBool
+		}
+	}
+
+	return table.columns
+}
+
+// flattenJSON flattens a JSON object into its values, keeping the order of
+// its keys. Keys of nested objects are joined to their parent key with a
+// dot, arrays are kept as JSON, and null values are empty. It returns nil
+// if the value is not an object.
+func flattenJSON(raw json.RawMessage, prefix string) ([]jsonValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, err
+	}
+
+	values := []jsonValue{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("error while reading json input: %w", err)
+		}
+
+		key := tok.(string)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("error while reading json input: %w", err)
+		}
+
+		nested, err := flattenJSON(value, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(nested) > 0 {
+			values = append(values, nested...)
+			continue
+		}
+
+		values = append(values, scalarJSON(key, value))
+	}
+
+	return values, nil
+}
+
+// scalarJSON converts a value that's not a nested object into its string
+// form and kind. Empty objects and arrays are kept as compact JSON.
+func scalarJSON(key string, raw json.RawMessage) jsonValue {
+	var v interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err == nil {
+		switch value := v.(type) {
+		case nil:
+			return jsonValue{key: key}
+		case string:
+			return jsonValue{key: key, value: value, kind: TypeString}
+		case json.Number:
+			return jsonValue{key: key, value: value.String(), kind: TypeNumber}
+		case bool:
+			return jsonValue{key: key, value: fmt.Sprint(value), kind: TypeBool}
+		}
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return jsonValue{key: key, value: string(raw), kind: TypeString}
+	}
+
+	return jsonValue{key: key, value: compact.String(), kind: TypeString}
+}
@@ -0,0 +1,124 @@
+package tabloid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTabloid_ParseColumns_json(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		titles  []string
+		want    map[string][]string
+		types   map[string]ColumnType
+		wantErr bool
+	}{
+		{
+			name: "array of objects",
+			input: `[
+				{"name": "web", "meta": {"zone": "us-east1", "labels": {"team": "a"}}, "replicas": 3, "ready": true},
+				{"name": "db", "meta": {"zone": "eu-west1"}, "replicas": 1.5, "ready": false, "ports": [5432]}
+			]`,
+			titles: []string{"name", "meta.zone", "meta.labels.team", "replicas", "ready", "ports"},
+			want: map[string][]string{
+				"name":           {"web", "db"},
+				"metazone":       {"us-east1", "eu-west1"},
+				"metalabelsteam": {"a", ""},
+				"replicas":       {"3", "1.5"},
+				"ready":          {"true", "false"},
+				"ports":          {"", "[5432]"},
+			},
+			types: map[string]ColumnType{
+				"name":     "",
+				"replicas": TypeNumber,
+				"ready":    TypeBool,
+				"ports":    "",
+			},
+		},
+		{
+			name:   "one object per line",
+			input:  "{\"a\": 1, \"b\": null}\n{\"b\": \"x\", \"c\": {}}\n",
+			titles: []string{"a", "b", "c"},
+			want: map[string][]string{
+				"a": {"1", ""},
+				"b": {"", "x"},
+				"c": {"", "{}"},
+			},
+			types: map[string]ColumnType{"a": TypeNumber, "b": "", "c": ""},
+		},
+		{
+			name:    "elements must be objects",
+			input:   `[{"a": 1}, 2]`,
+			wantErr: true,
+		},
+		{
+			name:    "flattened keys must be unique",
+			input:   `{"meta": {"app": "x"}, "meta.app": "y"}`,
+			wantErr: true,
+		},
+		{
+			name:    "keys must have unique names in expressions",
+			input:   `{"app-name": 1}` + "\n" + `{"App Name": 2}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			input:   `{"a": `,
+			wantErr: true,
+		},
+		{
+			name:    "empty input",
+			input:   `[]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := New(bytes.NewBufferString(tt.input))
+			tab.SetInputFormat(InputJSON)
+
+			got, err := tab.ParseColumns()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			assertEqual(t, titles(got), tt.titles, "titles = %q, want %q", titles(got), tt.titles)
+
+			byTitle := make(map[string]Column, len(got))
+			for _, c := range got {
+				byTitle[c.ExprTitle] = c
+			}
+
+			for title, want := range tt.want {
+				assertEqual(t, byTitle[title].Values, want, "column %q values = %q, want %q", title, byTitle[title].Values, want)
+			}
+
+			for title, want := range tt.types {
+				assertEqual(t, byTitle[title].Type, want, "column %q type = %q, want %q", title, byTitle[title].Type, want)
+			}
+		})
+	}
+}
+
+func TestTabloid_Filter_json(t *testing.T) {
+	tab := New(bytes.NewBufferString(`[{"name": "web", "replicas": 3, "ready": true}, {"name": "db", "replicas": 1, "ready": true}]`))
+	tab.SetInputFormat(InputJSON)
+
+	columns, err := tab.ParseColumns()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := tab.Filter(columns, "ready && replicas > 2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []string{"web"}
+	assertEqual(t, got[0].Values, want, "values = %q, want %q", got[0].Values, want)
+}
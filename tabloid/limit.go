@@ -0,0 +1,62 @@
+package tabloid
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Page returns the rows left after skipping the first offset ones, up to
+// limit rows, or all of them when limit is 0.
+func (t *Tabloid) Page(columns []Column, offset, limit int) []Column {
+	rows := rowCount(columns)
+
+	start := clampRows(offset, rows)
+	end := rows
+	if limit > 0 && start+limit < rows {
+		end = start + limit
+	}
+
+	return pickRows(columns, rowRange(start, end))
+}
+
+// Tail returns the last n rows, or all of them if there are fewer.
+func (t *Tabloid) Tail(columns []Column, n int) []Column {
+	rows := rowCount(columns)
+	return pickRows(columns, rowRange(rows-clampRows(n, rows), rows))
+}
+
+// Sample returns n rows picked at random, or all of them if there are fewer,
+// keeping their order. The same seed always picks the same rows from the
+// same input.
+func (t *Tabloid) Sample(columns []Column, n int, seed int64) []Column {
+	rows := rowCount(columns)
+
+	positions := rand.New(rand.NewSource(seed)).Perm(rows)[:clampRows(n, rows)]
+	sort.Ints(positions)
+
+	return pickRows(columns, positions)
+}
+
+// clampRows limits n to the range between 0 and the amount of rows.
+func clampRows(n, rows int) int {
+	if n < 0 {
+		return 0
+	}
+
+	if n > rows {
+		return rows
+	}
+
+	return n
+}
+
+// rowRange returns the positions of the rows from start, up to but not
+// including end.
+func rowRange(start, end int) []int {
+	positions := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		positions = append(positions, i)
+	}
+
+	return positions
+}
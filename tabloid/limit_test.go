@@ -0,0 +1,82 @@
+package tabloid
+
+import "testing"
+
+func limitColumns() []Column {
+	return []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"a", "b", "c", "d", "e"}},
+		{Title: "CPU", ExprTitle: "cpu", Values: []string{"1", "2", "3", "4", "5"}, Styled: []string{"1", "2", "\x1b[31m3\x1b[0m", "4", "5"}},
+	}
+}
+
+func TestTabloid_Page(t *testing.T) {
+	tests := []struct {
+		name          string
+		offset, limit int
+		want          []string
+	}{
+		{name: "limit", limit: 2, want: []string{"a", "b"}},
+		{name: "offset", offset: 3, want: []string{"d", "e"}},
+		{name: "offset and limit", offset: 1, limit: 2, want: []string{"b", "c"}},
+		{name: "limit past the end", offset: 4, limit: 10, want: []string{"e"}},
+		{name: "offset past the end", offset: 10, want: []string{}},
+		{name: "no limits", want: []string{"a", "b", "c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(nil).Page(limitColumns(), tt.offset, tt.limit)
+			assertEqual(t, got[0].Values, tt.want, "names = %q, want %q", got[0].Values, tt.want)
+		})
+	}
+}
+
+func TestTabloid_Tail(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{name: "last rows", n: 2, want: []string{"d", "e"}},
+		{name: "more than available", n: 10, want: []string{"a", "b", "c", "d", "e"}},
+		{name: "none", n: 0, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(nil).Tail(limitColumns(), tt.n)
+			assertEqual(t, got[0].Values, tt.want, "names = %q, want %q", got[0].Values, tt.want)
+		})
+	}
+
+	got := New(nil).Tail(limitColumns(), 3)
+	want := []string{"\x1b[31m3\x1b[0m", "4", "5"}
+	assertEqual(t, got[1].Styled, want, "styled = %q, want %q", got[1].Styled, want)
+}
+
+func TestTabloid_Sample(t *testing.T) {
+	tab := New(nil)
+
+	first := tab.Sample(limitColumns(), 3, 1)
+	if len(first[0].Values) != 3 {
+		t.Fatalf("sampled %d rows, want 3", len(first[0].Values))
+	}
+
+	again := tab.Sample(limitColumns(), 3, 1)
+	assertEqual(t, again[0].Values, first[0].Values, "same seed sampled %q, want %q", again[0].Values, first[0].Values)
+
+	for i := 1; i < len(first[0].Values); i++ {
+		if first[0].Values[i-1] >= first[0].Values[i] {
+			t.Errorf("sampled rows %q are not in their original order", first[0].Values)
+		}
+	}
+
+	cpus := map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5"}
+	for i, name := range first[0].Values {
+		if want := cpus[name]; first[1].Values[i] != want {
+			t.Errorf("row %q has cpu %q, want %q", name, first[1].Values[i], want)
+		}
+	}
+
+	all := tab.Sample(limitColumns(), 10, 1)
+	want := []string{"a", "b", "c", "d", "e"}
+	assertEqual(t, all[0].Values, want, "sampled %q, want %q", all[0].Values, want)
+}
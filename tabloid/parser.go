@@ -3,16 +3,86 @@ package tabloid
 import (
 	"bufio"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
 const endOfLine = -1
 
+// reAlignedGap matches the spaces between the values of aligned columns.
+var reAlignedGap = regexp.MustCompile(`\s{2,}`)
+
 // ParseHeading parses the heading of a tabloid table and returns a list of
 // columns with their respective start and end indexes. If it's the last column,
 // the end index is -1. It also returns an error if there are duplicate column
-// titles.
+// titles. If enabled, the heading is also used to detect the profile to
+// parse the rest of the input with, and whether its titles are separated by
+// tabs. Otherwise, tabs are expanded to the next tab stop. Comma and
+// tab-separated inputs use the heading as a record of titles instead, and
+// box-drawn tables split it on their cell separators.
 func (t *Tabloid) ParseHeading(heading string) ([]Column, error) {
+	t.parsedHeading = true
+	heading = StripANSI(heading)
+
+	if t.format == InputJSON {
+		return nil, fmt.Errorf("json inputs have no heading, they must be read with ParseColumns")
+	}
+
+	if t.isCSV() {
+		return csvTitles(t.parseCSVLine(heading))
+	}
+
+	if t.readsTable() && !t.boxed && startsBoxed(heading) {
+		t.boxed = true
+	}
+
+	if t.isBoxed() {
+		t.cellSeparated = hasCellSeparator(heading)
+		return newColumns(splitCells(heading))
+	}
+
+	if t.profile == nil && t.detect {
+		if p, found := DetectProfile(heading); found {
+			t.logger.Printf("detected profile %q from heading", p.Name)
+			t.profile = &p
+		}
+	}
+
+	if t.detectTabs && t.mode() == ParseAligned && isTabSeparated(heading) {
+		t.logger.Printf("detected titles separated by tabs in heading")
+		t.delimiter = "\t"
+	}
+
+	var (
+		columns []Column
+		err     error
+	)
+
+	switch t.mode() {
+	case ParseWhitespace:
+		columns, err = newColumns(joinTitles(strings.Fields(heading), t.profile.Titles))
+	case ParseDelimited:
+		columns, err = newColumns(splitDelimited(heading, t.delimiterInUse(), -1))
+	default:
+		columns, err = parseAlignedHeading(expandTabs(heading, t.tabWidth))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t.profile != nil {
+		for i, c := range columns {
+			columns[i].Type = t.profile.Types[c.Title]
+		}
+	}
+
+	return columns, nil
+}
+
+// parseAlignedHeading finds the columns of a heading whose titles are
+// separated by at least two spaces, using their positions as boundaries.
+func parseAlignedHeading(heading string) ([]Column, error) {
 	var columns []Column
 	uniques := make(map[string]struct{})
 
@@ -61,7 +131,222 @@ func (t *Tabloid) ParseHeading(heading string) ([]Column, error) {
 	return columns, nil
 }
 
+// ParseRow parses a single line using the columns returned by ParseHeading,
+// and returns a copy of them containing only the values from that line,
+// along with the computed columns of the profile, if any. It's useful to
+// process inputs that are streamed line by line. Since streamed lines, like
+// the ones of "kubectl get --watch", are aligned to their own widths
+// instead of the ones of the heading, aligned lines are split on runs of
+// two or more spaces when that finds one value per column.
+func (t *Tabloid) ParseRow(heading []Column, line string) []Column {
+	columns := make([]Column, 0, len(heading))
+	values, styled := t.parseValues(heading, line, true)
+
+	for pos, value := range values {
+		column := heading[pos]
+		column.Values = []string{value}
+		if styled != nil {
+			column.Styled = []string{styled[pos]}
+		}
+		columns = append(columns, column)
+	}
+
+	return t.addComputedColumns(columns)
+}
+
+// parseValues splits a line into the values of each column, without any
+// escape sequences, and, if enabled, their styled text. Streamed lines may
+// not be aligned to the heading.
+func (t *Tabloid) parseValues(columns []Column, line string, streamed bool) ([]string, []string) {
+	parsed := parseStyled(line)
+	values := t.splitLine(columns, parsed.plain, streamed)
+
+	if !t.keepANSI || t.isCSV() {
+		return values, nil
+	}
+
+	return values, parsed.styledValues(values)
+}
+
+// splitLine splits a line into the values of each column, using the parse
+// mode in use.
+func (t *Tabloid) splitLine(columns []Column, line string, streamed bool) []string {
+	if t.isCSV() {
+		return fitValues(t.parseCSVLine(line), len(columns))
+	}
+
+	if t.isBoxed() {
+		return fitValues(splitCells(line), len(columns))
+	}
+
+	switch t.mode() {
+	case ParseWhitespace:
+		return splitFields(line, len(columns))
+	case ParseDelimited:
+		return splitDelimited(line, t.delimiterInUse(), len(columns))
+	}
+
+	line = expandTabs(line, t.tabWidth)
+	if streamed {
+		if values := splitAligned(line); len(values) == len(columns) {
+			return values
+		}
+	}
+
+	return parseLine(columns, line)
+}
+
+// splitAligned splits a line on runs of two or more spaces, the minimum
+// padding between aligned columns.
+func splitAligned(line string) []string {
+	return reAlignedGap.Split(strings.TrimSpace(line), -1)
+}
+
+// splitFields splits a line on whitespace into the given amount of values,
+// with the last one taking the rest of the line.
+func splitFields(line string, n int) []string {
+	values := make([]string, 0, n)
+	rest := strings.TrimSpace(line)
+
+	for len(values) < n-1 && rest != "" {
+		end := strings.IndexAny(rest, " \t")
+		if end == -1 {
+			end = len(rest)
+		}
+
+		values = append(values, rest[:end])
+		rest = strings.TrimLeft(rest[end:], " \t")
+	}
+
+	values = append(values, rest)
+	for len(values) < n {
+		values = append(values, "")
+	}
+
+	return values
+}
+
+// splitDelimited splits a line on a delimiter, trimming every value. When n
+// is positive, exactly n values are returned, with the last one taking the
+// rest of the line. Otherwise, trailing empty values are removed.
+func splitDelimited(line, delimiter string, n int) []string {
+	values := strings.Split(line, delimiter)
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	if n <= 0 {
+		for len(values) > 0 && values[len(values)-1] == "" {
+			values = values[:len(values)-1]
+		}
+		return values
+	}
+
+	if len(values) > n {
+		values = append(values[:n-1], strings.Join(values[n-1:], delimiter))
+	}
+
+	for len(values) < n {
+		values = append(values, "")
+	}
+
+	return values
+}
+
+// fitValues pads or truncates values so there's exactly one per column.
+func fitValues(values []string, n int) []string {
+	if len(values) > n {
+		return values[:n]
+	}
+
+	for len(values) < n {
+		values = append(values, "")
+	}
+
+	return values
+}
+
+// joinTitles joins consecutive words of a heading that form one of the
+// given titles.
+func joinTitles(words, titles []string) []string {
+	joined := make([]string, 0, len(words))
+
+	for i := 0; i < len(words); {
+		word, size := words[i], 1
+		for _, title := range titles {
+			parts := strings.Fields(title)
+			if i+len(parts) <= len(words) && strings.Join(words[i:i+len(parts)], " ") == title {
+				word, size = title, len(parts)
+				break
+			}
+		}
+
+		joined = append(joined, word)
+		i += size
+	}
+
+	return joined
+}
+
+// newColumns creates the columns for the given titles, returning an error if
+// any of them is duplicated.
+func newColumns(titles []string) ([]Column, error) {
+	columns := make([]Column, 0, len(titles))
+	uniques := make(map[string]struct{}, len(titles))
+
+	for _, title := range titles {
+		if _, ok := uniques[title]; ok {
+			return nil, &DuplicateColumnTitleError{Title: title}
+		}
+		uniques[title] = struct{}{}
+
+		columns = append(columns, Column{
+			VisualPosition: len(columns) + 1,
+			Title:          title,
+			ExprTitle:      fnKey(title),
+		})
+	}
+
+	return columns, nil
+}
+
+// parseLine splits a line into the values of each column, based on their
+// start and end indexes. Lines shorter than the heading produce empty values
+// for the columns they don't reach.
+func parseLine(columns []Column, line string) []string {
+	values := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		// Calculate end index if it's the last column
+		startIdx, endIdx := column.StartIndex, column.EndIndex
+		if endIdx == endOfLine || endIdx > len(line) {
+			endIdx = len(line)
+		}
+
+		if startIdx > endIdx {
+			startIdx = endIdx
+		}
+
+		values = append(values, strings.TrimSpace(line[startIdx:endIdx]))
+	}
+
+	return values
+}
+
 func (t *Tabloid) ParseColumns() ([]Column, error) {
+	if t.isCSV() {
+		return t.parseCSV()
+	}
+
+	if t.format == InputJSON {
+		return t.parseJSON()
+	}
+
+	if t.readsTable() && detectBoxed(t.input.Bytes()) {
+		t.logger.Printf("detected a box-drawn table in input")
+		t.boxed = true
+	}
+
 	scanner := bufio.NewScanner(t.input)
 
 	var columns []Column
@@ -69,10 +354,16 @@ func (t *Tabloid) ParseColumns() ([]Column, error) {
 	for rowNumber := 1; scanner.Scan(); rowNumber++ {
 		line := scanner.Text()
 
-		// The first line is the header, so we use it to find the column titles
+		// Skip empty lines and the borders of box-drawn tables
+		if t.SkipLine(line) {
+			t.logger.Printf("omitting empty or border row found in line %d", rowNumber)
+			continue
+		}
+
+		// The first row is the header, so we use it to find the column titles
 		// the assumption here is that both target apps, kubectl and docker use
 		// a Go tabwriter with a padding of 3 spaces.
-		if rowNumber == 1 {
+		if !t.parsedHeading {
 			// Find the column titles
 			local, err := t.ParseHeading(line)
 			if err != nil {
@@ -85,24 +376,14 @@ func (t *Tabloid) ParseColumns() ([]Column, error) {
 			continue
 		}
 
-		// Skip empty lines
-		if strings.TrimSpace(line) == "" {
-			t.logger.Printf("omitting empty row found in line %d", rowNumber)
-			continue
-		}
-
-		// Parse each column's content
-		for pos := 0; pos < len(columns); pos++ {
-			// Calculate end index if it's the last column
-			endIdx := columns[pos].EndIndex
-			if endIdx == endOfLine {
-				endIdx = len(line)
-			}
-
-			value := strings.TrimSpace(line[columns[pos].StartIndex:endIdx])
-
-			// Store the value in the local copy of the metadata
+		// Parse each column's content and store the value in the local
+		// copy of the metadata
+		values, styled := t.parseValues(columns, line, false)
+		for pos, value := range values {
 			columns[pos].Values = append(columns[pos].Values, value)
+			if styled != nil {
+				columns[pos].Styled = append(columns[pos].Styled, styled[pos])
+			}
 		}
 	}
 
@@ -116,5 +397,5 @@ func (t *Tabloid) ParseColumns() ([]Column, error) {
 		return nil, fmt.Errorf("no data found in input")
 	}
 
-	return columns, nil
+	return t.addComputedColumns(columns), nil
 }
@@ -1,6 +1,7 @@
 package tabloid
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 )
@@ -84,3 +85,107 @@ func assertEqual(t *testing.T, got, want interface{}, msg string, args ...interf
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 }
+
+func TestTabloid_ParseColumns_tabs(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		delimiter string
+		detect    bool
+		want      map[string][]string
+	}{
+		{
+			name:   "titles separated by tabs are detected",
+			input:  "NAME\tNAMESPACE\tREVISION\nweb\tdefault\t3\napi server\tprod\t12\n",
+			detect: true,
+			want: map[string][]string{
+				"name":      {"web", "api server"},
+				"namespace": {"default", "prod"},
+				"revision":  {"3", "12"},
+			},
+		},
+		{
+			name:   "titles aligned with tabs and spaces are expanded",
+			input:  "NAME\t\tSTATUS  AGE\nweb-1\t\tRunning 8d\nweb-long-name\tPending 1d\n",
+			detect: true,
+			want: map[string][]string{
+				"name":   {"web-1", "web-long-name"},
+				"status": {"Running", "Pending"},
+				"age":    {"8d", "1d"},
+			},
+		},
+		{
+			name:   "titles aligned with a single tab and spaces are expanded",
+			input:  "NAME    AGE\tSTATUS\nweb     8d\tRunning\napi     12d\tPending\n",
+			detect: true,
+			want: map[string][]string{
+				"name":   {"web", "api"},
+				"age":    {"8d", "12d"},
+				"status": {"Running", "Pending"},
+			},
+		},
+		{
+			name:      "explicit delimiter",
+			input:     "NAME|STATUS|AGE\nweb-1|Running|8d\nweb-2||1d\n",
+			delimiter: "|",
+			want: map[string][]string{
+				"name":   {"web-1", "web-2"},
+				"status": {"Running", ""},
+				"age":    {"8d", "1d"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := New(bytes.NewBufferString(tt.input))
+			tab.DetectTabs(tt.detect)
+			tab.SetDelimiter(tt.delimiter)
+
+			got, err := tab.ParseColumns()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertEqual(t, len(got), len(tt.want), "got %d columns, want %d", len(got), len(tt.want))
+
+			for _, c := range got {
+				assertEqual(t, c.Values, tt.want[c.ExprTitle], "column %q values = %q, want %q", c.Title, c.Values, tt.want[c.ExprTitle])
+			}
+		})
+	}
+}
+
+func Test_expandTabs(t *testing.T) {
+	tests := []struct {
+		line  string
+		width int
+		want  string
+	}{
+		{line: "no tabs", width: 8, want: "no tabs"},
+		{line: "a\tb", width: 8, want: "a       b"},
+		{line: "12345678\tb", width: 8, want: "12345678        b"},
+		{line: "ab\t\tc", width: 4, want: "ab      c"},
+		{line: "a  \tb", width: 4, want: "a   b"},
+	}
+	for _, tt := range tests {
+		got := expandTabs(tt.line, tt.width)
+		assertEqual(t, got, tt.want, "expandTabs(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+	}
+}
+
+func Test_isTabSeparated(t *testing.T) {
+	tests := []struct {
+		heading string
+		want    bool
+	}{
+		{heading: "NAME\tNAMESPACE\tREVISION", want: true},
+		{heading: "NAME    \tNAMESPACE\tUPDATED          \tSTATUS", want: true},
+		{heading: "NAME\t\tSTATUS  AGE", want: false},
+		{heading: "NAME    AGE\tSTATUS", want: false},
+		{heading: "NAME  AGE", want: false},
+	}
+	for _, tt := range tests {
+		got := isTabSeparated(tt.heading)
+		assertEqual(t, got, tt.want, "isTabSeparated(%q) = %v, want %v", tt.heading, got, tt.want)
+	}
+}
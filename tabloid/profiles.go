@@ -0,0 +1,264 @@
+package tabloid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseMode defines how the boundaries between columns are found.
+type ParseMode string
+
+const (
+	// ParseAligned finds columns using the position of their titles, which
+	// must be separated by at least two spaces, like the output of a Go
+	// tabwriter. It's the default mode.
+	ParseAligned ParseMode = "aligned"
+
+	// ParseWhitespace splits titles and values on any amount of whitespace,
+	// with the last column taking the rest of the line, for outputs whose
+	// titles are separated by a single space or whose values are not aligned
+	// to their titles.
+	ParseWhitespace ParseMode = "whitespace"
+
+	// ParseDelimited splits titles and values on a delimiter, like a tab.
+	ParseDelimited ParseMode = "delimited"
+)
+
+// Profile configures how the output of a well-known command is parsed: how
+// column boundaries are found, the type of its columns and any computed
+// columns worth adding to it.
+type Profile struct {
+	Name        string
+	Description string
+
+	// Signature are the titles that, when all present in the heading,
+	// identify the output as coming from this command.
+	Signature []string
+
+	Mode      ParseMode
+	Delimiter string
+
+	// Titles are the titles containing spaces that must be kept together
+	// when splitting the heading on whitespace.
+	Titles []string
+
+	// Types are the column types, keyed by column title.
+	Types map[string]ColumnType
+
+	// Computed are the columns added after parsing, which are hidden unless
+	// selected by their title.
+	Computed []ComputedColumn
+}
+
+// ComputedColumn is a column computed from an expression, only added when
+// all the columns it requires exist.
+type ComputedColumn struct {
+	Title      string
+	Expression string
+	Requires   []string
+}
+
+// profiles are the built-in profiles, in the order they're tried when
+// detecting one from a heading, from the most to the least specific.
+var profiles = []Profile{
+	{
+		Name:        "docker-ps",
+		Description: "docker ps",
+		Signature:   []string{"CONTAINER ID", "IMAGE", "COMMAND", "CREATED", "STATUS", "PORTS", "NAMES"},
+		Mode:        ParseAligned,
+		Computed: []ComputedColumn{
+			{Title: "RUNNING", Expression: `status =~ "^Up"`, Requires: []string{"STATUS"}},
+		},
+	},
+	{
+		Name:        "docker-images",
+		Description: "docker images",
+		Signature:   []string{"REPOSITORY", "TAG", "IMAGE ID", "CREATED", "SIZE"},
+		Mode:        ParseAligned,
+		Computed: []ComputedColumn{
+			{Title: "SIZE BYTES", Expression: `bytesize(size)`, Requires: []string{"SIZE"}},
+		},
+	},
+	{
+		Name:        "helm-list",
+		Description: "helm list, whose columns are separated by tabs",
+		Signature:   []string{"NAME", "NAMESPACE", "REVISION", "UPDATED", "STATUS", "CHART"},
+		Mode:        ParseDelimited,
+		Delimiter:   "\t",
+		Types:       map[string]ColumnType{"REVISION": TypeNumber},
+	},
+	{
+		Name:        "ps-aux",
+		Description: "ps aux, whose titles are separated by a single space and whose last column contains spaces",
+		Signature:   []string{"USER", "PID", "%CPU", "%MEM", "VSZ", "RSS", "COMMAND"},
+		Mode:        ParseWhitespace,
+		Types: map[string]ColumnType{
+			"PID":  TypeNumber,
+			"%CPU": TypeNumber,
+			"%MEM": TypeNumber,
+			"VSZ":  TypeNumber,
+			"RSS":  TypeNumber,
+		},
+	},
+	{
+		Name:        "df",
+		Description: "df, whose numbers are right-aligned",
+		Signature:   []string{"Filesystem", "Mounted on"},
+		Mode:        ParseWhitespace,
+		Titles:      []string{"Mounted on"},
+		Types: map[string]ColumnType{
+			"1K-blocks":   TypeNumber,
+			"1024-blocks": TypeNumber,
+			"512-blocks":  TypeNumber,
+			"Used":        TypeNumber,
+			"Available":   TypeNumber,
+			"Use%":        TypeNumber,
+			"Capacity":    TypeNumber,
+			"Inodes":      TypeNumber,
+			"IUsed":       TypeNumber,
+			"IFree":       TypeNumber,
+			"IUse%":       TypeNumber,
+			"iused":       TypeNumber,
+			"ifree":       TypeNumber,
+			"%iused":      TypeNumber,
+		},
+		Computed: []ComputedColumn{
+			{Title: "Size bytes", Expression: `bytesize(size)`, Requires: []string{"Size"}},
+			{Title: "Used bytes", Expression: `bytesize(used)`, Requires: []string{"Size", "Used"}},
+			{Title: "Avail bytes", Expression: `bytesize(avail)`, Requires: []string{"Size", "Avail"}},
+		},
+	},
+	{
+		Name:        "kubectl",
+		Description: "kubectl get",
+		Signature:   []string{"NAME", "AGE"},
+		Mode:        ParseAligned,
+		Computed: []ComputedColumn{
+			{Title: "RESTART COUNT", Expression: `restartcount(restarts)`, Requires: []string{"RESTARTS"}},
+		},
+	},
+}
+
+// ProfileNames returns the names of all the built-in profiles, sorted.
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LookupProfile returns the built-in profile with the given name.
+func LookupProfile(name string) (Profile, error) {
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return Profile{}, fmt.Errorf("unknown profile %q, must be one of: %s", name, strings.Join(ProfileNames(), ", "))
+}
+
+// DetectProfile returns the first built-in profile whose signature matches
+// the given heading.
+func DetectProfile(heading string) (Profile, bool) {
+	words := " " + strings.Join(strings.Fields(heading), " ") + " "
+
+	for _, p := range profiles {
+		matched := true
+		for _, title := range p.Signature {
+			if !strings.Contains(words, " "+title+" ") {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return p, true
+		}
+	}
+
+	return Profile{}, false
+}
+
+// UseProfile sets the profile used to parse the input.
+func (t *Tabloid) UseProfile(p Profile) {
+	t.profile = &p
+}
+
+// DetectProfiles enables detecting the profile to use from the heading, if
+// none was set.
+func (t *Tabloid) DetectProfiles(enable bool) {
+	t.detect = enable
+}
+
+// ActiveProfile returns the profile being used to parse the input, either
+// because it was set or because it was detected.
+func (t *Tabloid) ActiveProfile() (Profile, bool) {
+	if t.profile == nil {
+		return Profile{}, false
+	}
+
+	return *t.profile, true
+}
+
+// mode returns the parse mode to use.
+func (t *Tabloid) mode() ParseMode {
+	if t.delimiter != "" {
+		return ParseDelimited
+	}
+
+	if t.profile == nil || t.profile.Mode == "" {
+		return ParseAligned
+	}
+
+	return t.profile.Mode
+}
+
+// addComputedColumns adds the computed columns of the profile, if any, as
+// hidden columns. Since they're a convenience, columns that can't be
+// computed for every row are skipped instead of failing.
+func (t *Tabloid) addComputedColumns(columns []Column) []Column {
+	if t.profile == nil {
+		return columns
+	}
+
+	for _, c := range t.profile.Computed {
+		if !hasTitles(columns, c.Requires) || hasColumn(columns, c.Title, fnKey(c.Title)) {
+			continue
+		}
+
+		computed, err := t.AddColumn(columns, c.Title, c.Expression)
+		if err != nil {
+			t.logger.Printf("skipping column %q of profile %q: %s", c.Title, t.profile.Name, err)
+			continue
+		}
+
+		columns = computed
+		columns[len(columns)-1].Hidden = true
+	}
+
+	return columns
+}
+
+// hasTitles reports whether columns with all the given titles exist.
+func hasTitles(columns []Column, titles []string) bool {
+	for _, title := range titles {
+		found := false
+		for _, c := range columns {
+			if c.Title == title {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
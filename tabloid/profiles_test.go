@@ -0,0 +1,287 @@
+package tabloid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetectProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		heading string
+		want    string
+		found   bool
+	}{
+		{
+			name:    "kubectl get pods",
+			heading: "NAMESPACE   NAME                        READY   STATUS    RESTARTS   AGE",
+			want:    "kubectl",
+			found:   true,
+		},
+		{
+			name:    "docker ps",
+			heading: "CONTAINER ID   IMAGE     COMMAND                  CREATED       STATUS       PORTS      NAMES",
+			want:    "docker-ps",
+			found:   true,
+		},
+		{
+			name:    "docker images",
+			heading: "REPOSITORY   TAG       IMAGE ID       CREATED       SIZE",
+			want:    "docker-images",
+			found:   true,
+		},
+		{
+			name:    "helm list",
+			heading: "NAME   \tNAMESPACE\tREVISION\tUPDATED                             \tSTATUS  \tCHART        \tAPP VERSION",
+			want:    "helm-list",
+			found:   true,
+		},
+		{
+			name:    "ps aux",
+			heading: "USER         PID %CPU %MEM    VSZ   RSS TTY      STAT START   TIME COMMAND",
+			want:    "ps-aux",
+			found:   true,
+		},
+		{
+			name:    "df",
+			heading: "Filesystem      Size  Used Avail Use% Mounted on",
+			want:    "df",
+			found:   true,
+		},
+		{
+			name:    "titles are matched as whole words",
+			heading: "NAMES   AGES",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := DetectProfile(tt.heading)
+			assertEqual(t, found, tt.found, "found = %v, want %v", found, tt.found)
+			assertEqual(t, got.Name, tt.want, "profile = %q, want %q", got.Name, tt.want)
+		})
+	}
+}
+
+func TestTabloid_ParseColumns_profiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   map[string][]string
+		hidden []string
+		types  map[string]ColumnType
+	}{
+		{
+			name: "ps aux",
+			input: "USER         PID %CPU %MEM    VSZ   RSS TTY      STAT START   TIME COMMAND\n" +
+				"root           1  0.0  0.1 167744 11872 ?        Ss   Oct18   0:04 /sbin/init splash\n" +
+				"postgres    8123 12.5  2.3 215432 94012 ?        Ss   10:01   1:23 postgres: writer process\n",
+			want: map[string][]string{
+				"user":    {"root", "postgres"},
+				"cpu":     {"0.0", "12.5"},
+				"command": {"/sbin/init splash", "postgres: writer process"},
+			},
+			types: map[string]ColumnType{"pid": TypeNumber, "cpu": TypeNumber, "user": ""},
+		},
+		{
+			name: "df with right-aligned numbers",
+			input: "Filesystem      Size  Used Avail Use% Mounted on\n" +
+				"overlay          59G   21G   36G  37% /\n" +
+				"/dev/sda1       976M  108M  802M  12% /boot/efi\n",
+			want: map[string][]string{
+				"filesystem":  {"overlay", "/dev/sda1"},
+				"size":        {"59G", "976M"},
+				"use":         {"37%", "12%"},
+				"mounted_on":  {"/", "/boot/efi"},
+				"size_bytes":  {"63350767616", "1023410176"},
+				"avail_bytes": {"38654705664", "840957952"},
+			},
+			hidden: []string{"size_bytes", "used_bytes", "avail_bytes"},
+			types:  map[string]ColumnType{"use": TypeNumber},
+		},
+		{
+			name: "helm list separated by tabs",
+			input: "NAME   \tNAMESPACE\tREVISION\tUPDATED                             \tSTATUS  \tCHART        \tAPP VERSION\n" +
+				"ingress\tdefault  \t3       \t2023-03-18 10:21:43.123 -0400 EDT\tdeployed\tnginx-1.2.3  \t1.2.3      \n",
+			want: map[string][]string{
+				"name":        {"ingress"},
+				"revision":    {"3"},
+				"updated":     {"2023-03-18 10:21:43.123 -0400 EDT"},
+				"app_version": {"1.2.3"},
+			},
+			types: map[string]ColumnType{"revision": TypeNumber},
+		},
+		{
+			name: "kubectl restart count",
+			input: "NAME    READY   STATUS    RESTARTS         AGE\n" +
+				"web-1   1/1     Running   592 (3m33s ago)   8d\n",
+			want: map[string][]string{
+				"restarts":      {"592 (3m33s ago)"},
+				"restart_count": {"592"},
+			},
+			hidden: []string{"restart_count"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tab := New(bytes.NewBufferString(tt.input))
+			tab.DetectProfiles(true)
+
+			got, err := tab.ParseColumns()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			byTitle := make(map[string]Column, len(got))
+			for _, c := range got {
+				byTitle[c.ExprTitle] = c
+			}
+
+			for title, want := range tt.want {
+				assertEqual(t, byTitle[title].Values, want, "column %q values = %q, want %q", title, byTitle[title].Values, want)
+			}
+
+			for _, title := range tt.hidden {
+				assertEqual(t, byTitle[title].Hidden, true, "column %q must be hidden", title)
+			}
+
+			for title, want := range tt.types {
+				assertEqual(t, byTitle[title].Type, want, "column %q type = %q, want %q", title, byTitle[title].Type, want)
+			}
+		})
+	}
+}
+
+func TestTabloid_ParseColumns_detectionKeepsBaseline(t *testing.T) {
+	// Outputs that were already parsed correctly before profiles existed
+	// must be parsed the same way when they're detected, except for the
+	// hidden columns profiles add. Outputs like "ps aux", whose titles are
+	// separated by single spaces, were merged into a few columns before, so
+	// they're only covered by the profile tests.
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "kubectl get pods",
+			input: "NAMESPACE     NAME                             READY   STATUS    RESTARTS      AGE\n" +
+				"team-a-apps   frontend-5c6c94684f-5kzbk        1/1     Running   0             8d\n" +
+				"team-a-apps   redis-leader-fb76b4755-6t5bk     1/1     Running   2 (3d ago)    8d\n",
+		},
+		{
+			name: "docker ps",
+			input: "CONTAINER ID   IMAGE          COMMAND                  CREATED        STATUS        PORTS     NAMES\n" +
+				"4c01db0b339c   nginx:1.25     \"/docker-entrypoint.…\"   2 hours ago    Up 2 hours    80/tcp    web\n" +
+				"d7886598dbe2   redis:7        \"docker-entrypoint.s…\"   5 days ago     Up 5 days               cache\n",
+		},
+		{
+			name: "docker images",
+			input: "REPOSITORY   TAG       IMAGE ID       CREATED        SIZE\n" +
+				"nginx        1.25      a8758716bb6a   2 weeks ago    187MB\n" +
+				"redis        7         7c1e4a3c1f4d   3 weeks ago    138MB\n",
+		},
+		{
+			name: "unknown command",
+			input: "ID   OWNER     STATE\n" +
+				"1    alice     open\n" +
+				"2    bob       closed\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parse := func(detect bool) []Column {
+				tab := New(bytes.NewBufferString(tt.input))
+				tab.DetectProfiles(detect)
+
+				cols, err := tab.ParseColumns()
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				return visibleColumns(cols)
+			}
+
+			baseline, detected := parse(false), parse(true)
+
+			assertEqual(t, titles(detected), titles(baseline), "titles = %q, want %q", titles(detected), titles(baseline))
+			for i := range baseline {
+				if i < len(detected) {
+					assertEqual(t, detected[i].Values, baseline[i].Values, "column %q values = %q, want %q", baseline[i].Title, detected[i].Values, baseline[i].Values)
+				}
+			}
+		})
+	}
+}
+
+func TestTabloid_Select_hidden(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name"},
+		{Title: "RESTART COUNT", ExprTitle: "restart_count", Hidden: true},
+		{Title: "RESTARTS", ExprTitle: "restarts"},
+	}
+
+	tests := []struct {
+		name      string
+		requested []string
+		want      []string
+	}{
+		{
+			name: "hidden by default",
+			want: []string{"NAME", "RESTARTS"},
+		},
+		{
+			name:      "not matched by globs",
+			requested: []string{"*restart*"},
+			want:      []string{"RESTARTS"},
+		},
+		{
+			name:      "not part of the remaining columns",
+			requested: []string{"restarts", "*"},
+			want:      []string{"RESTARTS", "NAME"},
+		},
+		{
+			name:      "not counted in positions",
+			requested: []string{"2"},
+			want:      []string{"RESTARTS"},
+		},
+		{
+			name:      "not matched by ranges",
+			requested: []string{"1-2"},
+			want:      []string{"NAME", "RESTARTS"},
+		},
+		{
+			name:      "selected by title",
+			requested: []string{"name", "restart_count"},
+			want:      []string{"NAME", "RESTART COUNT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(nil).Select(columns, tt.requested)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertEqual(t, titles(got), tt.want, "titles = %q, want %q", titles(got), tt.want)
+		})
+	}
+
+	if _, err := New(nil).Select(columns, []string{"3"}); err == nil {
+		t.Error("expected an error for a position only reachable by counting hidden columns")
+	}
+}
+
+func TestTabloid_Filter_typed(t *testing.T) {
+	columns := []Column{
+		{Title: "COMMAND", ExprTitle: "command", Values: []string{"init", "postgres", "bash"}},
+		{Title: "%CPU", ExprTitle: "cpu", Type: TypeNumber, Values: []string{"0.0", "12.5", "3"}},
+		{Title: "Use%", ExprTitle: "use", Type: TypeNumber, Values: []string{"10%", "95%", "50%"}},
+	}
+
+	got, err := New(nil).Filter(columns, "cpu > 2 && use < 90")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []string{"bash"}
+	assertEqual(t, got[0].Values, want, "values = %q, want %q", got[0].Values, want)
+}
@@ -2,36 +2,266 @@ package tabloid
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+// allColumns is the column selector that matches all the columns not
+// otherwise selected.
+const allColumns = "*"
+
+// Select returns the requested columns in the order they were requested.
+// Columns can be requested by their title, their position in the dataset
+// (starting from 1), a range of positions like "3-5", or a glob pattern like
+// "*ip*" matched against their titles. A single "*" expands to all the
+// columns not explicitly requested, in their original order. Requesting a
+// single column as "name:TITLE" also renames it to the given title, which
+// can contain colons, but can't be the title of another returned column.
+// Hidden
+// columns are only returned when requested by their title, and are left out
+// when counting positions.
 func (t *Tabloid) Select(columns []Column, requestedColumnNames []string) ([]Column, error) {
-	// If there are no requested columns, we return them all
+	// If there are no requested columns, we return all the visible ones
 	if len(requestedColumnNames) == 0 {
-		return columns, nil
+		return visibleColumns(columns), nil
 	}
 
-	returnedColumns := make([]Column, 0, len(requestedColumnNames))
-	for _, v := range requestedColumnNames {
-		var column Column
+	resolved := make([][]int, len(requestedColumnNames))
+	renames := make([]string, len(requestedColumnNames))
+	used := make(map[int]struct{})
+
+	for i, v := range requestedColumnNames {
+		if strings.TrimSpace(v) == allColumns {
+			continue
+		}
+
+		if idx := strings.Index(v, ":"); idx > 0 {
+			v, renames[i] = v[:idx], strings.TrimSpace(v[idx+1:])
+			if renames[i] == "" {
+				return nil, fmt.Errorf("column %q must be renamed to a non-empty title", v)
+			}
+		}
+
+		positions, err := matchColumns(columns, v)
+		if err != nil {
+			return nil, err
+		}
+
+		if renames[i] != "" && len(positions) != 1 {
+			return nil, fmt.Errorf("unable to rename %q to %q: it matches %d columns", v, renames[i], len(positions))
+		}
+
+		for _, pos := range positions {
+			used[pos] = struct{}{}
+		}
+		resolved[i] = positions
+	}
 
-		for _, c := range columns {
-			if c.Title == v || strings.ToLower(c.Title) == v || c.ExprTitle == v {
-				column = c
-				break
+	var (
+		returnedColumns = make([]Column, 0, len(requestedColumnNames))
+		renamed         []int
+	)
+	for i, v := range requestedColumnNames {
+		if strings.TrimSpace(v) == allColumns {
+			for pos, c := range columns {
+				if _, found := used[pos]; !found && !c.Hidden {
+					returnedColumns = append(returnedColumns, c)
+				}
 			}
+			continue
 		}
 
-		if column.ExprTitle == "" {
-			return nil, fmt.Errorf("column %q does not exist in the input dataset", v)
+		for _, pos := range resolved[i] {
+			column := columns[pos]
+			if renames[i] != "" {
+				column = renameColumn(column, renames[i])
+				renamed = append(renamed, len(returnedColumns))
+			}
+			returnedColumns = append(returnedColumns, column)
 		}
+	}
 
-		returnedColumns = append(returnedColumns, column)
+	for _, pos := range renamed {
+		for other, c := range returnedColumns {
+			if other != pos && (c.Title == returnedColumns[pos].Title || c.ExprTitle == returnedColumns[pos].ExprTitle) {
+				return nil, &DuplicateColumnTitleError{Title: returnedColumns[pos].Title}
+			}
+		}
 	}
 
 	return returnedColumns, nil
 }
 
+// Exclude returns all the columns except the ones matching the given names,
+// which follow the same rules as the ones given to Select.
+func (t *Tabloid) Exclude(columns []Column, excludedColumnNames []string) ([]Column, error) {
+	if len(excludedColumnNames) == 0 {
+		return columns, nil
+	}
+
+	excluded := make(map[int]struct{})
+	for _, v := range excludedColumnNames {
+		positions, err := matchColumns(columns, v)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pos := range positions {
+			excluded[pos] = struct{}{}
+		}
+	}
+
+	returnedColumns := make([]Column, 0, len(columns))
+	for pos, c := range columns {
+		if _, found := excluded[pos]; !found {
+			returnedColumns = append(returnedColumns, c)
+		}
+	}
+
+	return returnedColumns, nil
+}
+
+// Rename changes the title of the column matching the given name, which
+// follows the same rules as the ones given to Select but must match a single
+// column. The normalized title is updated too, so expressions must use the
+// new title afterwards.
+func (t *Tabloid) Rename(columns []Column, name, title string) ([]Column, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("column %q must be renamed to a non-empty title", name)
+	}
+
+	positions, err := matchColumns(columns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(positions) != 1 {
+		return nil, fmt.Errorf("unable to rename %q to %q: it matches %d columns", name, title, len(positions))
+	}
+
+	renamed := renameColumn(columns[positions[0]], title)
+	for pos, c := range columns {
+		if pos != positions[0] && (c.Title == renamed.Title || c.ExprTitle == renamed.ExprTitle) {
+			return nil, &DuplicateColumnTitleError{Title: title}
+		}
+	}
+
+	returnedColumns := make([]Column, len(columns))
+	copy(returnedColumns, columns)
+	returnedColumns[positions[0]] = renamed
+
+	t.logger.Printf("renamed column %q to %q", columns[positions[0]].Title, title)
+	return returnedColumns, nil
+}
+
+// renameColumn returns a copy of the column with the given title.
+func renameColumn(column Column, title string) Column {
+	column.Title = title
+	column.ExprTitle = fnKey(title)
+	return column
+}
+
+var rePositionRange = regexp.MustCompile(`^(\d+)(?:-(\d+))?$`)
+
+// matchColumns returns the positions of the columns matching the given name,
+// position, range of positions or glob pattern. Positions and patterns only
+// match visible columns.
+func matchColumns(columns []Column, name string) ([]int, error) {
+	name = strings.TrimSpace(name)
+
+	if pos, found := findColumn(columns, name); found {
+		return []int{pos}, nil
+	}
+
+	if m := rePositionRange.FindStringSubmatch(name); m != nil {
+		from, _ := strconv.Atoi(m[1])
+		to := from
+		if m[2] != "" {
+			to, _ = strconv.Atoi(m[2])
+		}
+
+		visible := make([]int, 0, len(columns))
+		for pos, c := range columns {
+			if !c.Hidden {
+				visible = append(visible, pos)
+			}
+		}
+
+		if from < 1 || to > len(visible) || from > to {
+			return nil, fmt.Errorf("column position %q is out of range: input has %d columns", name, len(visible))
+		}
+
+		return visible[from-1 : to], nil
+	}
+
+	if strings.ContainsAny(name, "*?[") {
+		var positions []int
+		for pos, c := range columns {
+			if c.Hidden {
+				continue
+			}
+
+			for _, title := range []string{c.Title, strings.ToLower(c.Title), c.ExprTitle} {
+				matched, err := path.Match(name, title)
+				if err != nil {
+					return nil, fmt.Errorf("invalid column pattern %q: %w", name, err)
+				}
+
+				if matched {
+					positions = append(positions, pos)
+					break
+				}
+			}
+		}
+
+		if len(positions) == 0 {
+			return nil, fmt.Errorf("no columns matching %q exist in the input dataset", name)
+		}
+		return positions, nil
+	}
+
+	return nil, fmt.Errorf("column %q does not exist in the input dataset", name)
+}
+
+// visibleColumns returns the columns that are not hidden.
+func visibleColumns(columns []Column) []Column {
+	for _, c := range columns {
+		if c.Hidden {
+			visible := make([]Column, 0, len(columns))
+			for _, c := range columns {
+				if !c.Hidden {
+					visible = append(visible, c)
+				}
+			}
+			return visible
+		}
+	}
+
+	return columns
+}
+
+// findColumn returns the position of the column matching the given name,
+// either by its original title, its lowercased title or its normalized
+// title. Exact title matches take precedence.
+func findColumn(columns []Column, name string) (int, bool) {
+	for pos, c := range columns {
+		if c.Title == name {
+			return pos, true
+		}
+	}
+
+	for pos, c := range columns {
+		if strings.ToLower(c.Title) == name || c.ExprTitle == name {
+			return pos, true
+		}
+	}
+
+	return -1, false
+}
+
 // func (t *Tabloid) Select(columns []Column, data []map[string]interface{}, requestedColumns []string) ([]map[string]interface{}, error) {
 // 	foundColumnNames := make([]string, 0, len(requestedColumns))
 
@@ -0,0 +1,155 @@
+package tabloid
+
+import "testing"
+
+func TestTabloid_Select(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name"},
+		{Title: "READY", ExprTitle: "ready"},
+		{Title: "IP", ExprTitle: "ip"},
+		{Title: "NODE", ExprTitle: "node"},
+		{Title: "NOMINATED NODE", ExprTitle: "nominated_node"},
+		{Title: "HOST IP", ExprTitle: "host_ip"},
+	}
+
+	tests := []struct {
+		name      string
+		requested []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name: "no columns requested",
+			want: []string{"NAME", "READY", "IP", "NODE", "NOMINATED NODE", "HOST IP"},
+		},
+		{
+			name:      "by title",
+			requested: []string{"ready", "NAME", "nominated_node"},
+			want:      []string{"READY", "NAME", "NOMINATED NODE"},
+		},
+		{
+			name:      "by position and range",
+			requested: []string{"1", "3-4"},
+			want:      []string{"NAME", "IP", "NODE"},
+		},
+		{
+			name:      "by glob",
+			requested: []string{"name", "*ip*"},
+			want:      []string{"NAME", "IP", "HOST IP"},
+		},
+		{
+			name:      "remaining columns",
+			requested: []string{"node", "*", "name"},
+			want:      []string{"NODE", "READY", "IP", "NOMINATED NODE", "HOST IP", "NAME"},
+		},
+		{
+			name:      "renamed",
+			requested: []string{"name:POD", "node:Node Name"},
+			want:      []string{"POD", "Node Name"},
+		},
+		{
+			name:      "renamed to a title with colons",
+			requested: []string{"name:POD:NAME", "ip"},
+			want:      []string{"POD:NAME", "IP"},
+		},
+		{
+			name:      "renamed to the title of another column",
+			requested: []string{"name:node", "node"},
+			wantErr:   true,
+		},
+		{
+			name:      "renamed to the same title",
+			requested: []string{"name:ADDRESS", "ip:Address"},
+			wantErr:   true,
+		},
+		{
+			name:      "renaming multiple columns",
+			requested: []string{"*ip*:ADDRESS"},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown column",
+			requested: []string{"status"},
+			wantErr:   true,
+		},
+		{
+			name:      "position out of range",
+			requested: []string{"7"},
+			wantErr:   true,
+		},
+		{
+			name:      "reversed range",
+			requested: []string{"4-2"},
+			wantErr:   true,
+		},
+		{
+			name:      "glob without matches",
+			requested: []string{"*status*"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(nil).Select(columns, tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			assertEqual(t, titles(got), tt.want, "titles = %q, want %q", titles(got), tt.want)
+		})
+	}
+}
+
+func TestTabloid_Exclude(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name"},
+		{Title: "READY", ExprTitle: "ready"},
+		{Title: "NOMINATED NODE", ExprTitle: "nominated_node"},
+		{Title: "READINESS GATES", ExprTitle: "readiness_gates"},
+	}
+
+	got, err := New(nil).Exclude(columns, []string{"nominated_node", "READINESS GATES"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []string{"NAME", "READY"}
+	assertEqual(t, titles(got), want, "titles = %q, want %q", titles(got), want)
+
+	if _, err := New(nil).Exclude(columns, []string{"status"}); err == nil {
+		t.Errorf("expected error when excluding an unknown column")
+	}
+}
+
+func titles(columns []Column) []string {
+	out := make([]string, 0, len(columns))
+	for _, c := range columns {
+		out = append(out, c.Title)
+	}
+	return out
+}
+
+func TestTabloid_Rename(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name"},
+		{Title: "NAMESPACE", ExprTitle: "namespace"},
+	}
+
+	got, err := New(nil).Rename(columns, "namespace", "NS")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	assertEqual(t, got[1].Title, "NS", "title = %q, want %q", got[1].Title, "NS")
+	assertEqual(t, got[1].ExprTitle, "ns", "normalized title = %q, want %q", got[1].ExprTitle, "ns")
+	assertEqual(t, columns[1].Title, "NAMESPACE", "original column was modified: %q", columns[1].Title)
+
+	if _, err := New(nil).Rename(columns, "namespace", "Name"); err == nil {
+		t.Errorf("expected error when renaming to an existing title")
+	}
+}
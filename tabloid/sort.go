@@ -0,0 +1,51 @@
+package tabloid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sort returns the rows ordered by the values of the given column. Values
+// are compared as numbers when both of them are numeric, and as strings
+// otherwise, with numbers first. Rows with equal values keep their original
+// order.
+func (t *Tabloid) Sort(columns []Column, by string, descending bool) ([]Column, error) {
+	pos, found := findColumn(columns, strings.TrimSpace(by))
+	if !found {
+		return nil, fmt.Errorf("column %q does not exist in the input dataset", by)
+	}
+
+	values := columns[pos].Values
+	positions := make([]int, rowCount(columns))
+	for i := range positions {
+		positions[i] = i
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		a, b := values[positions[i]], values[positions[j]]
+		if descending {
+			a, b = b, a
+		}
+		return lessValue(a, b)
+	})
+
+	return pickRows(columns, positions), nil
+}
+
+// lessValue compares two values numerically if both are numbers, including
+// percentages, or as strings otherwise. Numbers go before strings, so mixed
+// columns are still sorted consistently.
+func lessValue(a, b string) bool {
+	na, okA := parseNumber(a)
+	nb, okB := parseNumber(b)
+
+	switch {
+	case okA && okB:
+		return na < nb
+	case okA != okB:
+		return okA
+	}
+
+	return a < b
+}
@@ -0,0 +1,67 @@
+package tabloid
+
+import "testing"
+
+func TestTabloid_Sort(t *testing.T) {
+	columns := []Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web", "db", "cache", "api"}},
+		{Title: "CPU", ExprTitle: "cpu", Values: []string{"10", "9", "100", "9"}},
+		{Title: "MEMORY", ExprTitle: "memory", Values: []string{"<none>", "200", "1000", "-"}},
+	}
+
+	tests := []struct {
+		name       string
+		by         string
+		descending bool
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "strings ascending",
+			by:   "name",
+			want: []string{"api", "cache", "db", "web"},
+		},
+		{
+			name:       "strings descending",
+			by:         "NAME",
+			descending: true,
+			want:       []string{"web", "db", "cache", "api"},
+		},
+		{
+			name: "numbers are compared numerically and ties keep their order",
+			by:   "cpu",
+			want: []string{"db", "api", "web", "cache"},
+		},
+		{
+			name: "numbers go before strings",
+			by:   "memory",
+			want: []string{"db", "cache", "api", "web"},
+		},
+		{
+			name:       "numbers go after strings when descending",
+			by:         "memory",
+			descending: true,
+			want:       []string{"web", "api", "cache", "db"},
+		},
+		{
+			name:    "unknown column",
+			by:      "image",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(nil).Sort(columns, tt.by, tt.descending)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			assertEqual(t, got[0].Values, tt.want, "sorted names = %q, want %q", got[0].Values, tt.want)
+		})
+	}
+}
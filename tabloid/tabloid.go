@@ -14,8 +14,21 @@ type Logger interface {
 }
 
 type Tabloid struct {
-	input  *bytes.Buffer
-	logger Logger
+	input   *bytes.Buffer
+	logger  Logger
+	profile *Profile
+	detect  bool
+
+	delimiter  string
+	detectTabs bool
+	tabWidth   int
+	format     InputFormat
+	keepANSI   bool
+
+	boxed         bool
+	cellSeparated bool
+	parsedHeading bool
+	readRows      bool
 }
 
 type Column struct {
@@ -25,12 +38,26 @@ type Column struct {
 	StartIndex     int
 	EndIndex       int
 	Values         []string
+
+	// Type defines how values are handed to expressions. Columns without a
+	// type are strings.
+	Type ColumnType
+
+	// Hidden columns can be used in expressions, but they're only
+	// displayed when selected by their title.
+	Hidden bool
+
+	// Styled are the values with terminal styling, either their original
+	// one, when enabled with KeepANSI, or the one added by Colorize or
+	// HighlightMatches. Columns without styled values leave it empty.
+	Styled []string
 }
 
 func New(input *bytes.Buffer) *Tabloid {
 	return &Tabloid{
-		input:  input,
-		logger: log.New(io.Discard, "🚨 --> ", log.Lshortfile),
+		input:    input,
+		logger:   log.New(io.Discard, "🚨 --> ", log.Lshortfile),
+		tabWidth: defaultTabWidth,
 	}
 }
 
@@ -0,0 +1,83 @@
+package tabloid
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultTabWidth is the distance between tab stops used when expanding
+// tabs, the same one used by most terminals.
+const defaultTabWidth = 8
+
+// SetDelimiter makes the parser split titles and values on the given
+// delimiter, like a tab, instead of finding columns by the position of
+// their titles.
+func (t *Tabloid) SetDelimiter(delimiter string) {
+	t.delimiter = delimiter
+}
+
+// DetectTabs enables detecting headings whose titles are separated by tabs,
+// to split titles and values on tabs.
+func (t *Tabloid) DetectTabs(enable bool) {
+	t.detectTabs = enable
+}
+
+// SetTabWidth sets the distance between tab stops used to expand tabs in
+// inputs that mix tabs and spaces to align their columns.
+func (t *Tabloid) SetTabWidth(width int) {
+	t.tabWidth = width
+}
+
+// delimiterInUse returns the delimiter to split lines on, either the one
+// set or the one from the profile.
+func (t *Tabloid) delimiterInUse() string {
+	if t.delimiter != "" || t.profile == nil {
+		return t.delimiter
+	}
+
+	return t.profile.Delimiter
+}
+
+// reSpacedTitles matches two or more spaces followed by a title, instead of
+// by the tab they pad to, like helm does.
+var reSpacedTitles = regexp.MustCompile(` {2,}[^\t ]`)
+
+// isTabSeparated reports whether the titles of a heading are separated by
+// tabs. Consecutive tabs, or titles also separated by two or more spaces,
+// mean tabs are used to align the titles instead, like a tabwriter padding
+// with tabs would do.
+func isTabSeparated(heading string) bool {
+	heading = strings.TrimSpace(heading)
+	return strings.Contains(heading, "\t") && !strings.Contains(heading, "\t\t") && !reSpacedTitles.MatchString(heading)
+}
+
+// expandTabs replaces the tabs in a line with the amount of spaces needed to
+// reach the next tab stop.
+func expandTabs(line string, width int) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+
+	if width < 1 {
+		width = defaultTabWidth
+	}
+
+	var (
+		b      strings.Builder
+		column int
+	)
+
+	for _, r := range line {
+		if r == '\t' {
+			spaces := width - column%width
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+
+		b.WriteRune(r)
+		column++
+	}
+
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package tabloid
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ColumnType defines how the values of a column are handed to expressions
+// and compared when sorting.
+type ColumnType string
+
+// Supported column types. Columns without a type are strings.
+const (
+	TypeString ColumnType = "string"
+	TypeNumber ColumnType = "number"
+	TypeBool   ColumnType = "bool"
+)
+
+// typedValue converts a value to the type of its column. Number columns
+// produce float64 values, ignoring a trailing percent sign, so "37%" is 37,
+// and bool columns produce bool values. Values that can't be converted are
+// kept as strings.
+func typedValue(columnType ColumnType, value string) interface{} {
+	switch columnType {
+	case TypeNumber:
+		if n, ok := parseNumber(value); ok {
+			return n
+		}
+	case TypeBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
+
+// parseNumber parses a number, ignoring a trailing percent sign.
+func parseNumber(value string) (float64, bool) {
+	n, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	return n, err == nil
+}
+
+// Numeric reports whether the column holds numbers, either because it's a
+// number column or because all its values, ignoring empty ones, are
+// numbers. Columns without any value are not numeric.
+func (c Column) Numeric() bool {
+	if c.Type == TypeNumber {
+		return true
+	}
+
+	found := false
+	for _, v := range c.Values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+
+		if _, ok := parseNumber(v); !ok {
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
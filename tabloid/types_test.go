@@ -0,0 +1,22 @@
+package tabloid
+
+import "testing"
+
+func TestColumn_Numeric(t *testing.T) {
+	tests := []struct {
+		name   string
+		column Column
+		want   bool
+	}{
+		{name: "number column", column: Column{Type: TypeNumber, Values: []string{"21G"}}, want: true},
+		{name: "numbers", column: Column{Values: []string{"1", "", "2.5", "37%"}}, want: true},
+		{name: "mixed values", column: Column{Values: []string{"1", "a"}}},
+		{name: "empty values", column: Column{Values: []string{"", ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.column.Numeric()
+			assertEqual(t, got, tt.want, "Numeric() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -14,6 +14,32 @@ func (e *DuplicateColumnTitleError) Error() string {
 	return fmt.Sprintf("duplicate column title found: %q -- unable to work with non-unique column titles", e.Title)
 }
 
+// hasColumn reports whether a column with the given title, or the given
+// normalized title, already exists.
+func hasColumn(columns []Column, title, exprTitle string) bool {
+	for _, c := range columns {
+		if c.Title == title || c.ExprTitle == exprTitle {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutHidden returns the columns without the hidden ones with the given
+// title or normalized title, so they can be replaced by a new column.
+func withoutHidden(columns []Column, title, exprTitle string) []Column {
+	kept := make([]Column, 0, len(columns))
+	for _, c := range columns {
+		if c.Hidden && (c.Title == title || c.ExprTitle == exprTitle) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+
+	return kept
+}
+
 func fnKey(s string) string {
 	s = strings.ToLower(s)
 
@@ -0,0 +1,72 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// openTerminal opens the controlling terminal for reading and writing, so
+// tabloid can interact with the user even when its standard input is being
+// used for the data to parse.
+func openTerminal() (*os.File, error) {
+	return os.OpenFile("/dev/tty", os.O_RDWR, 0)
+}
+
+// makeRaw puts the terminal in raw mode, so keys can be read as soon as
+// they're pressed, and returns a function to restore its previous state.
+// It relies on stty to avoid platform-specific system calls.
+func makeRaw(tty *os.File) (func() error, error) {
+	state, err := stty(tty, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read terminal state: %w", err)
+	}
+
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("unable to set terminal in raw mode: %w", err)
+	}
+
+	return func() error {
+		_, err := stty(tty, strings.TrimSpace(state))
+		return err
+	}, nil
+}
+
+// terminalSize returns the width and height of the terminal.
+func terminalSize(tty *os.File) (int, int, error) {
+	out, err := stty(tty, "size")
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to read terminal size: %w", err)
+	}
+
+	var height, width int
+	if _, err := fmt.Sscanf(out, "%d %d", &height, &width); err != nil {
+		return 0, 0, fmt.Errorf("unable to parse terminal size %q: %w", out, err)
+	}
+
+	return width, height, nil
+}
+
+// stty runs the stty command against the given terminal.
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// notifyResize relays the signals sent when the terminal is resized to the
+// given channel.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
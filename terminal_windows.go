@@ -0,0 +1,33 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// errRawTerminal is returned on Windows, where the terminal can't be put
+// in raw mode nor queried for its size, since that relies on stty.
+var errRawTerminal = errors.New("raw terminals are not supported on windows")
+
+// openTerminal opens the console for reading and writing. It's enough for
+// the REPL and --confirm, which only read whole lines, while interactive
+// mode, which needs a raw terminal, is rejected before getting here.
+func openTerminal() (*os.File, error) {
+	return os.OpenFile("CONIN$", os.O_RDWR, 0)
+}
+
+// makeRaw is not supported on Windows.
+func makeRaw(tty *os.File) (func() error, error) {
+	return nil, errRawTerminal
+}
+
+// terminalSize is not supported on Windows.
+func terminalSize(tty *os.File) (int, int, error) {
+	return 0, 0, errRawTerminal
+}
+
+// notifyResize does nothing on Windows, where the size of the terminal is
+// never read.
+func notifyResize(c chan<- os.Signal) {}
@@ -3,17 +3,40 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"strings"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
 )
 
 func sliceToTabulated(slice []string) string {
 	var s bytes.Buffer
-	for pos, v := range examples {
+	for pos, v := range slice {
 		s.WriteString(fmt.Sprintf("  %s", v))
 
-		if pos != len(examples)-1 {
+		if pos != len(slice)-1 {
 			s.WriteString("\n")
 		}
 	}
 
 	return s.String()
 }
+
+// splitAssignment splits a string in the form of "name=value" into its name
+// and value, using the first equal sign found as the separator.
+func splitAssignment(s string) (string, string, error) {
+	name, value, found := strings.Cut(s, "=")
+	if !found || strings.TrimSpace(name) == "" || strings.TrimSpace(value) == "" {
+		return "", "", fmt.Errorf("%q must be in the form of name=value", s)
+	}
+
+	return strings.TrimSpace(name), strings.TrimSpace(value), nil
+}
+
+// rowsIn returns the amount of rows in the columns.
+func rowsIn(columns []tabloid.Column) int {
+	if len(columns) == 0 {
+		return 0
+	}
+
+	return len(columns[0].Values)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+// watch runs the command on an interval, re-rendering the filtered table
+// each time until the context is cancelled. Cells that changed since the
+// previous run are highlighted, comparing them by row and column title.
+// Other output formats are meant for other programs, so every run is
+// appended to the output instead, and errors are written to errw.
+func watch(ctx context.Context, w, errw io.Writer, args []string, opts settings) error {
+	ticker := time.NewTicker(opts.watch)
+	defer ticker.Stop()
+
+	var previous []tabloid.Column
+
+	table := opts.output == outputTable || opts.output == ""
+
+	for {
+		var screen bytes.Buffer
+		prefix, screenErr := "", errw
+		if table {
+			prefix, screenErr = clearScreen, &screen
+			fmt.Fprintf(&screen, "Every %s: %s    %s\n\n", opts.watch, strings.Join(args, " "), time.Now().Format(time.RFC1123))
+		}
+
+		current, err := watchOnce(ctx, &screen, screenErr, args, opts, previous)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			fmt.Fprintf(screenErr, "Error: %s\n", err)
+		} else {
+			previous = current
+		}
+
+		if _, err := io.WriteString(w, prefix+screen.String()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchOnce runs the command a single time and renders its output, using
+// the output format in the settings. The command's standard error, and the
+// columns dropped to fit the table, are written to errw.
+func watchOnce(ctx context.Context, w, errw io.Writer, args []string, opts settings, previous []tabloid.Column) ([]tabloid.Column, error) {
+	stdout, stderr, err := runSource(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if stderr != "" {
+		fmt.Fprintf(errw, "%s\n", strings.TrimSpace(stderr))
+	}
+
+	tab, cols, err := load(stdout, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := process(tab, cols, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.output != outputTable && opts.output != "" {
+		return output, printOutput(w, output, opts)
+	}
+
+	highlight := highlightChanges(output, previous, opts)
+	hidden, err := writeTable(w, output, opts, chainDecorators(styledCells(output), boldTitles(opts), highlight))
+	if err != nil {
+		return nil, err
+	}
+
+	printHidden(errw, hidden)
+
+	return output, nil
+}
+
+// highlightChanges returns a decorator that highlights the cells whose value
+// differs from the one in the same row and column of the previous output,
+// if there's one and the output is colorized, or nil otherwise.
+func highlightChanges(current, previous []tabloid.Column, opts settings) cellDecorator {
+	if opts.noHighlight || !opts.useColor || previous == nil {
+		return nil
+	}
+
+	before := make(map[string][]string, len(previous))
+	for _, c := range previous {
+		before[c.ExprTitle] = c.Values
+	}
+
+	return func(row, col int, text string) string {
+		if row == headerRow {
+			return text
+		}
+
+		values, found := before[current[col].ExprTitle]
+		if found && row < len(values) && values[row] == current[col].Values[row] {
+			return text
+		}
+
+		return highlightStart + text + tabloid.ANSIReset
+	}
+}
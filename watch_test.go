@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/patrickdappollonio/tabloid/tabloid"
+)
+
+func Test_highlightChanges(t *testing.T) {
+	previous := []tabloid.Column{
+		{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "web-2"}},
+		{Title: "STATUS", ExprTitle: "status", Values: []string{"Running", "Pending"}},
+	}
+
+	tests := []struct {
+		name        string
+		current     []tabloid.Column
+		highlighted [][]bool
+	}{
+		{
+			name:        "no changes",
+			current:     previous,
+			highlighted: [][]bool{{false, false}, {false, false}},
+		},
+		{
+			name: "changed cell",
+			current: []tabloid.Column{
+				{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "web-2"}},
+				{Title: "STATUS", ExprTitle: "status", Values: []string{"Running", "Running"}},
+			},
+			highlighted: [][]bool{{false, false}, {false, true}},
+		},
+		{
+			name: "added row",
+			current: []tabloid.Column{
+				{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "web-2", "web-3"}},
+				{Title: "STATUS", ExprTitle: "status", Values: []string{"Running", "Pending", "Pending"}},
+			},
+			highlighted: [][]bool{{false, false}, {false, false}, {true, true}},
+		},
+		{
+			name: "removed row",
+			current: []tabloid.Column{
+				{Title: "NAME", ExprTitle: "name", Values: []string{"web-2"}},
+				{Title: "STATUS", ExprTitle: "status", Values: []string{"Pending"}},
+			},
+			highlighted: [][]bool{{true, true}},
+		},
+		{
+			name: "reordered and added columns",
+			current: []tabloid.Column{
+				{Title: "STATUS", ExprTitle: "status", Values: []string{"Running", "Pending"}},
+				{Title: "NAME", ExprTitle: "name", Values: []string{"web-1", "web-2"}},
+				{Title: "AGE", ExprTitle: "age", Values: []string{"1d", "2d"}},
+			},
+			highlighted: [][]bool{{false, false, true}, {false, false, true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decorate := highlightChanges(tt.current, previous, settings{useColor: true})
+
+			for col, c := range tt.current {
+				if got := decorate(headerRow, col, c.Title); got != c.Title {
+					t.Errorf("title %q was decorated as %q", c.Title, got)
+				}
+			}
+
+			for row, cols := range tt.highlighted {
+				for col, want := range cols {
+					text := tt.current[col].Values[row]
+					got := decorate(row, col, text) != text
+					if got != want {
+						t.Errorf("row %d, column %q highlighted = %v, want %v", row, tt.current[col].Title, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_highlightChanges_disabled(t *testing.T) {
+	previous := []tabloid.Column{{Title: "NAME", ExprTitle: "name", Values: []string{"web-1"}}}
+	current := []tabloid.Column{{Title: "NAME", ExprTitle: "name", Values: []string{"web-2"}}}
+
+	tests := map[string]struct {
+		previous []tabloid.Column
+		opts     settings
+	}{
+		"without colors":      {previous: previous, opts: settings{}},
+		"with --no-highlight": {previous: previous, opts: settings{useColor: true, noHighlight: true}},
+		"on the first run":    {opts: settings{useColor: true}},
+	}
+	for name, tt := range tests {
+		if decorate := highlightChanges(current, tt.previous, tt.opts); decorate != nil {
+			t.Errorf("%s: expected no highlighting, got %q", name, decorate(0, 0, "web-2"))
+		}
+	}
+}
+
+func Test_runSource(t *testing.T) {
+	stdout, stderr, err := runSource(context.Background(), []string{"sh", "-c", "echo out; echo err >&2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if stdout.String() != "out\n" || stderr != "err\n" {
+		t.Errorf("stdout = %q, stderr = %q, want %q and %q", stdout.String(), stderr, "out\n", "err\n")
+	}
+
+	_, _, err = runSource(context.Background(), []string{"sh", "-c", "echo broken >&2; exit 3"})
+	if err == nil || !strings.Contains(err.Error(), "exited with code 3: broken") {
+		t.Errorf("error = %v, want the exit code and standard error", err)
+	}
+
+	if _, _, err := runSource(context.Background(), []string{"tabloid-missing-command"}); err == nil {
+		t.Error("expected an error for a missing command")
+	}
+}
+
+func Test_watchOnce_outputFormats(t *testing.T) {
+	args := []string{"printf", "%s", podsInput}
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: outputNDJSON, want: `{"namespace":"default","name":"web-1","ready":"1/1","status":"Running","restarts":"0","age":"8d"}`},
+		{output: outputCSV, want: "NAMESPACE,NAME,READY,STATUS,RESTARTS,AGE\n"},
+		{output: outputTable, want: "NAMESPACE   NAME    READY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			opts := testSettings()
+			opts.output = tt.output
+
+			var out, errOut bytes.Buffer
+			if _, err := watchOnce(context.Background(), &out, &errOut, args, opts, nil); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !strings.HasPrefix(out.String(), tt.want) {
+				t.Errorf("output = %q, want it to start with %q", out.String(), tt.want)
+			}
+		})
+	}
+}